@@ -0,0 +1,471 @@
+package steadicam
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// AnimationOptions configures animated exports of an Operator's tracking shots
+type AnimationOptions struct {
+	// Captions adds a bar under each frame naming its tracking shot and the
+	// interactions that led to it
+	Captions bool
+	// MinDelay is the shortest time a frame is shown; headless stages move far
+	// faster than anyone can watch. Defaults to 500ms.
+	MinDelay time.Duration
+	// MaxDelay caps the time a frame is shown, so long waits don't stall the
+	// animation. Defaults to 3s.
+	MaxDelay time.Duration
+	// FinalDelay is how long the last frame holds before looping. Defaults to 2s.
+	FinalDelay time.Duration
+}
+
+// Animation defaults
+const (
+	defaultMinFrameDelay   = 500 * time.Millisecond
+	defaultMaxFrameDelay   = 3 * time.Second
+	defaultFinalFrameDelay = 2 * time.Second
+	captionBarHeight       = 20
+)
+
+// withDefaults fills in unset options
+func (o AnimationOptions) withDefaults() AnimationOptions {
+	if o.MinDelay <= 0 {
+		o.MinDelay = defaultMinFrameDelay
+	}
+	if o.MaxDelay <= 0 {
+		o.MaxDelay = defaultMaxFrameDelay
+	}
+	if o.MaxDelay < o.MinDelay {
+		o.MaxDelay = o.MinDelay
+	}
+	if o.FinalDelay <= 0 {
+		o.FinalDelay = defaultFinalFrameDelay
+	}
+	return o
+}
+
+// animationFrame is one tracking shot ready for encoding
+type animationFrame struct {
+	image *image.RGBA
+	delay time.Duration
+}
+
+// SaveGIF assembles the run's tracking shots into a looping animated GIF at
+// path, so a whole run can be reviewed at once instead of frame by frame.
+// Each frame is shown for the time that passed until the next shot was taken,
+// kept between MinDelay and MaxDelay.
+//
+// Example:
+//
+//	result := op.Stop()
+//	result.SaveGIF("screenshots/search.gif", steadicam.AnimationOptions{Captions: true})
+func (r *StageResult) SaveGIF(path string, opts AnimationOptions) error {
+	return saveAnimation(path, r.WriteGIF, opts)
+}
+
+// WriteGIF writes the run's tracking shots as a looping animated GIF. See SaveGIF.
+func (r *StageResult) WriteGIF(w io.Writer, opts AnimationOptions) error {
+	frames, err := r.animationFrames(opts)
+	if err != nil {
+		return err
+	}
+
+	animation := &gif.GIF{}
+	for _, frame := range frames {
+		animation.Image = append(animation.Image, palettedFrame(frame.image))
+		animation.Delay = append(animation.Delay, gifDelay(frame.delay))
+	}
+	return gif.EncodeAll(w, animation)
+}
+
+// SaveAPNG assembles the run's tracking shots into a looping animated PNG at
+// path. Unlike GIF it keeps every color exactly. See SaveGIF for timing.
+func (r *StageResult) SaveAPNG(path string, opts AnimationOptions) error {
+	return saveAnimation(path, r.WriteAPNG, opts)
+}
+
+// WriteAPNG writes the run's tracking shots as a looping animated PNG. See SaveAPNG.
+func (r *StageResult) WriteAPNG(w io.Writer, opts AnimationOptions) error {
+	frames, err := r.animationFrames(opts)
+	if err != nil {
+		return err
+	}
+	return encodeAPNG(w, frames)
+}
+
+// saveAnimation writes an animation to path, creating its directory
+func saveAnimation(path string, write func(io.Writer, AnimationOptions) error, opts AnimationOptions) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file, opts); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// animationFrames loads the tracking shots in capture order, timed and captioned
+func (r *StageResult) animationFrames(opts AnimationOptions) ([]animationFrame, error) {
+	opts = opts.withDefaults()
+
+	type shot struct {
+		filename string
+		caption  string
+		taken    time.Time
+	}
+	var shots []shot
+	var since []StageAction // Interactions since the previous shot
+	for _, action := range r.Actions {
+		tracking, ok := action.Details.(TrackingShot)
+		if ok && !strings.EqualFold(filepath.Ext(tracking.Filename), ".png") {
+			continue // SVG screenshots have no bitmap to animate
+		}
+		if action.Type != "screenshot" || !ok {
+			since = append(since, action)
+			continue
+		}
+		shots = append(shots, shot{
+			filename: tracking.Filename,
+			caption:  caption(tracking.Label, since),
+			taken:    action.Timestamp,
+		})
+		since = nil
+	}
+	if len(shots) == 0 {
+		return nil, errors.New("no tracking shots to animate; capture frames with an Operator")
+	}
+
+	// Frames may differ in size after a resize; lay them all out on the largest
+	images := make([]image.Image, len(shots))
+	var width, height int
+	for i, s := range shots {
+		img, err := loadFrame(s.filename)
+		if err != nil {
+			return nil, err
+		}
+		images[i] = img
+		width = max(width, img.Bounds().Dx())
+		height = max(height, img.Bounds().Dy())
+	}
+
+	frames := make([]animationFrame, len(shots))
+	for i, s := range shots {
+		canvas := frameCanvas(images[i], width, height)
+		if opts.Captions {
+			canvas = withCaptionBar(canvas, s.caption)
+		}
+
+		delay := opts.FinalDelay
+		if i+1 < len(shots) {
+			delay = clampDelay(shots[i+1].taken.Sub(s.taken), opts.MinDelay, opts.MaxDelay)
+		}
+		frames[i] = animationFrame{image: canvas, delay: delay}
+	}
+	return frames, nil
+}
+
+// loadFrame decodes a tracking shot from disk
+func loadFrame(filename string) (image.Image, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return img, nil
+}
+
+// frameCanvas copies a frame onto an opaque canvas of the animation's size,
+// filling any extra space with the frame's own background
+func frameCanvas(img image.Image, width, height int) *image.RGBA {
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	background := color.RGBAModel.Convert(img.At(img.Bounds().Min.X, img.Bounds().Min.Y)).(color.RGBA)
+	background.A = 255
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(canvas, img.Bounds().Sub(img.Bounds().Min), img, img.Bounds().Min, draw.Over)
+	return canvas
+}
+
+// withCaptionBar extends a frame with a caption bar underneath
+func withCaptionBar(frame *image.RGBA, text string) *image.RGBA {
+	bounds := frame.Bounds()
+	img := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()+captionBarHeight))
+	draw.Draw(img, bounds, frame, image.Point{}, draw.Src)
+
+	bar := image.Rect(0, bounds.Dy(), bounds.Dx(), img.Bounds().Dy())
+	draw.Draw(img, bar, image.NewUniform(color.RGBA{40, 40, 40, 255}), image.Point{}, draw.Src)
+
+	face := basicfont.Face7x13
+	maxChars := (bounds.Dx() - 8) / face.Advance
+	if runes := []rune(text); maxChars > 0 && len(runes) > maxChars {
+		text = string(runes[:max(maxChars-3, 0)]) + "..."
+	}
+
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(color.RGBA{220, 220, 220, 255}),
+		Face: face,
+		Dot:  fixed.P(4, bounds.Dy()+captionBarHeight-(captionBarHeight-face.Ascent)/2),
+	}
+	drawer.DrawString(text)
+	return img
+}
+
+// caption names a tracking shot and the interactions that led to it
+func caption(label string, actions []StageAction) string {
+	var steps []string
+	var typed strings.Builder
+	flushTyped := func() {
+		if typed.Len() > 0 {
+			steps = append(steps, fmt.Sprintf("type %q", typed.String()))
+			typed.Reset()
+		}
+	}
+
+	for _, action := range actions {
+		if action.Type == "type" {
+			typed.WriteString(fmt.Sprint(action.Details))
+			continue
+		}
+		flushTyped()
+
+		switch action.Type {
+		case "keypress":
+			steps = append(steps, fmt.Sprint(action.Details))
+		case "paste":
+			steps = append(steps, fmt.Sprintf("paste %q", action.Details))
+		case "mouse", "resize", "focus", "send", "advance_time", "replay":
+			steps = append(steps, fmt.Sprintf("%s %v", strings.ReplaceAll(action.Type, "_", " "), action.Details))
+		}
+	}
+	flushTyped()
+
+	if len(steps) == 0 {
+		return label
+	}
+	return label + ": " + strings.Join(steps, ", ")
+}
+
+// clampDelay keeps a frame delay within bounds
+func clampDelay(delay, minDelay, maxDelay time.Duration) time.Duration {
+	if delay < minDelay {
+		return minDelay
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// gifDelay converts a delay to GIF's hundredths of a second
+func gifDelay(delay time.Duration) int {
+	return max(int(delay/(10*time.Millisecond)), 2) // Viewers slow down anything faster
+}
+
+// palettedFrame converts a frame to a paletted image. Anti-aliased glyph edges
+// blend every text color into the background, so frames easily pass GIF's 256
+// colors; the palette keeps the most frequent ones, which keeps backgrounds and
+// text colors exact, and rarer edge shades take their nearest entry.
+func palettedFrame(img *image.RGBA) *image.Paletted {
+	bounds := img.Bounds()
+	counts := make(map[color.RGBA]int)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			counts[img.RGBAAt(x, y)]++
+		}
+	}
+
+	colors := make([]color.RGBA, 0, len(counts))
+	for c := range counts {
+		colors = append(colors, c)
+	}
+	// Ties break on the color itself so identical frames get identical palettes
+	sort.Slice(colors, func(i, j int) bool {
+		if counts[colors[i]] != counts[colors[j]] {
+			return counts[colors[i]] > counts[colors[j]]
+		}
+		return packRGBA(colors[i]) < packRGBA(colors[j])
+	})
+	colors = colors[:min(len(colors), 256)]
+
+	palette := make(color.Palette, len(colors))
+	index := make(map[color.RGBA]uint8, len(counts))
+	for i, c := range colors {
+		palette[i] = c
+		index[c] = uint8(i)
+	}
+
+	paletted := image.NewPaletted(bounds, palette)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			i, ok := index[c]
+			if !ok {
+				i = uint8(palette.Index(c))
+				index[c] = i
+			}
+			paletted.SetColorIndex(x, y, i)
+		}
+	}
+	return paletted
+}
+
+// packRGBA packs a color into one comparable integer
+func packRGBA(c color.RGBA) uint32 {
+	return uint32(c.R)<<24 | uint32(c.G)<<16 | uint32(c.B)<<8 | uint32(c.A)
+}
+
+// encodeAPNG writes frames of equal size as an animated PNG that loops forever.
+//
+// Each frame is encoded with image/png, whose image data becomes the frame's
+// IDAT chunks (first frame) or fdAT chunks (later frames), preceded by an fcTL
+// chunk carrying its delay. The file has a single IHDR, so every frame must
+// encode to the same one; image/png picks RGB or RGBA by opacity, and a frame
+// that differs from the first is an error rather than a corrupt animation.
+func encodeAPNG(w io.Writer, frames []animationFrame) error {
+	encoded := make([]map[string][][]byte, len(frames))
+	for i, frame := range frames {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, frame.image); err != nil {
+			return err
+		}
+		chunks, err := pngChunks(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if i > 0 && !bytes.Equal(chunks["IHDR"][0], encoded[0]["IHDR"][0]) {
+			return fmt.Errorf("apng: frame %d is %s but the first frame is %s", i, describeIHDR(chunks["IHDR"][0]), describeIHDR(encoded[0]["IHDR"][0]))
+		}
+		encoded[i] = chunks
+	}
+
+	if _, err := io.WriteString(w, "\x89PNG\r\n\x1a\n"); err != nil {
+		return err
+	}
+
+	var sequence uint32
+	for i, frame := range frames {
+		chunks := encoded[i]
+		if i == 0 {
+			if err := writePNGChunk(w, "IHDR", chunks["IHDR"][0]); err != nil {
+				return err
+			}
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+			binary.BigEndian.PutUint32(actl[4:], 0) // Loop forever
+			if err := writePNGChunk(w, "acTL", actl); err != nil {
+				return err
+			}
+		}
+
+		bounds := frame.image.Bounds()
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], sequence)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(bounds.Dy()))
+		// x and y offsets stay zero: every frame covers the whole canvas
+		binary.BigEndian.PutUint16(fctl[20:], uint16(min(int(frame.delay/time.Millisecond), 65535)))
+		binary.BigEndian.PutUint16(fctl[22:], 1000)
+		// Dispose and blend ops stay zero: APNG_DISPOSE_OP_NONE, APNG_BLEND_OP_SOURCE
+		if err := writePNGChunk(w, "fcTL", fctl); err != nil {
+			return err
+		}
+		sequence++
+
+		for _, data := range chunks["IDAT"] {
+			if i == 0 {
+				if err := writePNGChunk(w, "IDAT", data); err != nil {
+					return err
+				}
+				continue
+			}
+			fdat := make([]byte, 4+len(data))
+			binary.BigEndian.PutUint32(fdat, sequence)
+			copy(fdat[4:], data)
+			if err := writePNGChunk(w, "fdAT", fdat); err != nil {
+				return err
+			}
+			sequence++
+		}
+	}
+
+	return writePNGChunk(w, "IEND", nil)
+}
+
+// describeIHDR summarizes an IHDR chunk's size, bit depth and color type
+func describeIHDR(ihdr []byte) string {
+	return fmt.Sprintf("%dx%d, %d-bit color type %d",
+		binary.BigEndian.Uint32(ihdr[0:]), binary.BigEndian.Uint32(ihdr[4:]), ihdr[8], ihdr[9])
+}
+
+// pngChunks splits an encoded PNG into its chunks' data, grouped by type
+func pngChunks(data []byte) (map[string][][]byte, error) {
+	const signatureLength = 8
+	if len(data) < signatureLength {
+		return nil, errors.New("png: truncated image")
+	}
+
+	chunks := make(map[string][][]byte)
+	for rest := data[signatureLength:]; len(rest) > 0; {
+		if len(rest) < 12 {
+			return nil, errors.New("png: truncated chunk")
+		}
+		length := binary.BigEndian.Uint32(rest)
+		if uint64(len(rest)) < 12+uint64(length) {
+			return nil, errors.New("png: truncated chunk")
+		}
+		kind := string(rest[4:8])
+		chunks[kind] = append(chunks[kind], rest[8:8+length])
+		rest = rest[12+length:]
+	}
+	return chunks, nil
+}
+
+// writePNGChunk writes a chunk with its length and CRC
+func writePNGChunk(w io.Writer, kind string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], kind)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+
+	for _, part := range [][]byte{header, data, footer} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package steadicam
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// animatedRun captures three tracking shots, the last after a resize
+func animatedRun(t *testing.T) *StageResult {
+	op := NewOperatorForModel(t, &mockREPLForInteractions{mode: "ready"}, t.TempDir())
+	op.StageDirector.config.TypingSpeed = 0
+	op.StageDirector.config.LosslessSync = true // Enter leaves the view alone; don't wait for it to change
+
+	op.Start().CaptureTrackingShot("start")
+	op.Type("hi")
+	op.CaptureTrackingShot("typed")
+	op.Resize(40, 10).PressEnterWithTrackingShot("small")
+	result := op.Stop()
+	require.True(t, result.Success, result.ErrorMessage)
+	require.Len(t, result.TrackingShots(), 3)
+	return result
+}
+
+// TestStageResult_SaveGIF tests assembling tracking shots into an animated GIF
+func TestStageResult_SaveGIF(t *testing.T) {
+	result := animatedRun(t)
+
+	path := filepath.Join(t.TempDir(), "film", "run.gif")
+	require.NoError(t, result.SaveGIF(path, AnimationOptions{Captions: true, FinalDelay: 4 * time.Second}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	require.Len(t, animation.Image, 3)
+	assert.Equal(t, []int{50, 50, 400}, animation.Delay, "fast stages slow to MinDelay; the last frame holds")
+	assert.Equal(t, 0, animation.LoopCount)
+
+	// Every frame is the size of the largest, plus the caption bar;
+	// Operator frames use 7x14 cells, Go Mono at 12px
+	for _, frame := range animation.Image {
+		assert.Equal(t, 80*7, frame.Bounds().Dx())
+		assert.Equal(t, 24*14+captionBarHeight, frame.Bounds().Dy())
+	}
+}
+
+// TestStageResult_WriteAPNG tests the animated PNG encoder
+func TestStageResult_WriteAPNG(t *testing.T) {
+	result := animatedRun(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteAPNG(&buf, AnimationOptions{MinDelay: 250 * time.Millisecond}))
+
+	// Decoders without APNG support show the first frame
+	first, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 80*7, first.Bounds().Dx())
+	assert.Equal(t, 24*14, first.Bounds().Dy())
+
+	chunks, err := pngChunks(buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, chunks["acTL"], 1)
+	assert.Equal(t, uint32(3), binary.BigEndian.Uint32(chunks["acTL"][0]))
+	require.Len(t, chunks["fcTL"], 3)
+	assert.Equal(t, uint16(250), binary.BigEndian.Uint16(chunks["fcTL"][0][20:]))
+	assert.Equal(t, uint16(2000), binary.BigEndian.Uint16(chunks["fcTL"][2][20:]))
+	require.NotEmpty(t, chunks["fdAT"])
+
+	// fcTL and fdAT chunks share one gapless sequence
+	var sequence []uint32
+	for _, kind := range []string{"fcTL", "fdAT"} {
+		for _, chunk := range chunks[kind] {
+			sequence = append(sequence, binary.BigEndian.Uint32(chunk))
+		}
+	}
+	assert.Len(t, sequence, 3+len(chunks["fdAT"]))
+	assert.ElementsMatch(t, sequence, countTo(uint32(len(sequence))))
+}
+
+// TestEncodeAPNG_MismatchedFrames tests that frames needing a different IHDR are rejected
+func TestEncodeAPNG_MismatchedFrames(t *testing.T) {
+	opaque := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	draw.Draw(opaque, opaque.Bounds(), image.NewUniform(color.RGBA{10, 20, 30, 255}), image.Point{}, draw.Src)
+	translucent := image.NewRGBA(image.Rect(0, 0, 4, 4)) // Encodes as RGBA rather than RGB
+
+	var buf bytes.Buffer
+	err := encodeAPNG(&buf, []animationFrame{{image: opaque}, {image: translucent}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "frame 1 is 4x4, 8-bit color type 6 but the first frame is 4x4, 8-bit color type 2")
+	assert.Zero(t, buf.Len(), "nothing is written for a rejected animation")
+
+	require.NoError(t, encodeAPNG(&buf, []animationFrame{{image: opaque}, {image: opaque}}))
+}
+
+// TestPalettedFrame_KeepsTextColors tests that anti-aliased text keeps its exact colors in a GIF
+func TestPalettedFrame_KeepsTextColors(t *testing.T) {
+	// None of these are web-safe, so snapping to a fixed palette would move them
+	colors := []color.RGBA{
+		{230, 57, 70, 255}, {42, 157, 143, 255}, {233, 196, 106, 255},
+		{244, 162, 97, 255}, {69, 123, 157, 255}, {168, 218, 220, 255},
+	}
+	var text strings.Builder
+	for i, c := range colors {
+		if i == 3 {
+			text.WriteString("\r\n")
+		}
+		fmt.Fprintf(&text, "\x1b[38;2;%d;%d;%dmHello world \x1b[0m", c.R, c.G, c.B)
+	}
+
+	// The Operator's default font: anti-aliased Go Mono at 12px
+	rs := NewRenderingStage(Config{Width: 40, Height: 2, FontSize: 12})
+	rs.RenderText(text.String())
+	img := rs.renderImage()
+
+	distinct := make(map[color.RGBA]bool)
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			distinct[img.RGBAAt(x, y)] = true
+		}
+	}
+	require.Greater(t, len(distinct), 256, "the frame should need more colors than a GIF holds")
+
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, &gif.GIF{Image: []*image.Paletted{palettedFrame(img)}, Delay: []int{0}}))
+	animation, err := gif.DecodeAll(&buf)
+	require.NoError(t, err)
+
+	frame := animation.Image[0]
+	kept := make(map[color.RGBA]bool)
+	for y := 0; y < frame.Bounds().Dy(); y++ {
+		for x := 0; x < frame.Bounds().Dx(); x++ {
+			kept[color.RGBAModel.Convert(frame.At(x, y)).(color.RGBA)] = true
+		}
+	}
+	for _, c := range colors {
+		assert.True(t, kept[c], "text color %v should survive exactly", c)
+	}
+	assert.True(t, kept[img.RGBAAt(0, 0)], "the background should survive exactly")
+}
+
+// TestCaption tests describing the interactions behind a frame
+func TestCaption(t *testing.T) {
+	actions := []StageAction{
+		{Type: "type", Details: "h"},
+		{Type: "type", Details: "i"},
+		{Type: "assertion", Details: "contains=hi"},
+		{Type: "keypress", Details: "enter"},
+		{Type: "resize", Details: "40x10"},
+	}
+	assert.Equal(t, `typed: type "hi", enter, resize 40x10`, caption("typed", actions))
+	assert.Equal(t, "start", caption("start", nil))
+}
+
+// TestStageResult_SaveGIFWithoutShots tests that plain stages have nothing to animate
+func TestStageResult_SaveGIFWithoutShots(t *testing.T) {
+	result := &StageResult{Actions: []StageAction{{Type: "keypress", Details: "enter"}}}
+	err := result.WriteGIF(&bytes.Buffer{}, AnimationOptions{})
+	assert.ErrorContains(t, err, "no tracking shots")
+}
+
+// countTo returns 0..n-1
+func countTo(n uint32) []uint32 {
+	numbers := make([]uint32, n)
+	for i := range numbers {
+		numbers[i] = uint32(i)
+	}
+	return numbers
+}
@@ -2,7 +2,9 @@ package steadicam
 
 import (
 	"fmt"
+	"html"
 	"html/template"
+	"image/color"
 	"os"
 	"strings"
 )
@@ -27,87 +29,150 @@ func ConvertANSIToTerminalHTML(ansiPath string) (template.HTML, error) {
 	return template.HTML(htmlContent), nil
 }
 
-// convertANSIToHTML converts ANSI escape sequences to HTML with colors using a state machine
+// convertANSIToHTML converts ANSI output to HTML by playing it into a virtual
+// terminal and reading back the resulting screen, so cursor movement, erases
+// and overwrites are honored exactly as a terminal would show them
 func convertANSIToHTML(ansiText string) string {
-	var result strings.Builder
-	i := 0
+	lines := strings.Split(ansiText, "\n")
 
-	for i < len(ansiText) {
-		char := ansiText[i]
+	width := 1
+	for _, line := range lines {
+		width = max(width, displayWidth(stripANSI(line)))
+	}
 
-		// Skip carriage returns
-		if char == '\r' {
-			i++
-			continue
-		}
+	screen := NewScreen(width, len(lines))
+	// Captured output uses bare newlines, which a cooked TTY turns into CRLF
+	screen.WriteString("\x1b[20h")
+	screen.WriteString(ansiText)
 
-		// Convert newlines to HTML
-		if char == '\n' {
-			result.WriteString("<br>")
-			i++
-			continue
+	return screen.HTML()
+}
+
+// HTML renders the active screen buffer as HTML, one <br>-separated row per
+// terminal line, with styled runs wrapped in spans. Trailing blank cells and
+// rows are omitted.
+func (s *Screen) HTML() string {
+	grid := s.Cells()
+
+	// Drop trailing blank rows
+	last := len(grid) - 1
+	for last >= 0 && isBlankRow(grid[last]) {
+		last--
+	}
+
+	rows := make([]string, 0, last+1)
+	for _, row := range grid[:last+1] {
+		rows = append(rows, rowHTML(row))
+	}
+	return strings.Join(rows, "<br>")
+}
+
+// isBlankCell reports whether a cell renders as empty space
+func isBlankCell(cell Cell) bool {
+	return cell.Grapheme == " " && !cell.Style.Background.Set && !cell.Style.Reverse
+}
+
+// isBlankRow reports whether every cell in the row renders as empty space
+func isBlankRow(row []Cell) bool {
+	for _, cell := range row {
+		if !isBlankCell(cell) {
+			return false
 		}
+	}
+	return true
+}
 
-		// Check for ANSI escape sequence
-		if char == '\x1b' && i+1 < len(ansiText) && ansiText[i+1] == '[' {
-			// Find the end of the ANSI sequence
-			i += 2 // Skip \x1b[
-
-			// Collect the sequence
-			var seqBuilder strings.Builder
-			for i < len(ansiText) {
-				c := ansiText[i]
-				if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') {
-					// Found the terminator
-					seqBuilder.WriteByte(c)
-					i++
-					break
-				}
-				seqBuilder.WriteByte(c)
-				i++
-			}
-
-			sequence := seqBuilder.String()
-
-			// Convert known sequences to HTML
-			if html := convertSequenceToHTML(sequence); html != "" {
-				result.WriteString(html)
-			}
-			// Skip unknown sequences (cursor movements, clears, etc.)
+// rowHTML renders one row of cells, grouping runs with identical style
+func rowHTML(row []Cell) string {
+	// Trim trailing cells that render as nothing
+	end := len(row)
+	for end > 0 && isBlankCell(row[end-1]) {
+		end--
+	}
+
+	var result strings.Builder
+	for start := 0; start < end; {
+		style := row[start].Style
+		var text strings.Builder
+		i := start
+		for ; i < end && row[i].Style == style; i++ {
+			text.WriteString(row[i].Grapheme)
+		}
 
+		escaped := html.EscapeString(text.String())
+		if css := styleCSS(style); css != "" {
+			result.WriteString(`<span style="` + css + `">` + escaped + `</span>`)
 		} else {
-			// Regular character
-			result.WriteByte(char)
-			i++
+			result.WriteString(escaped)
 		}
+		start = i
 	}
-
 	return result.String()
 }
 
-// convertSequenceToHTML converts a single ANSI sequence to HTML
-func convertSequenceToHTML(sequence string) string {
-	switch sequence {
-	case "0m":
-		return "</span>"
-	case "1;38;5;39m":
-		return `<span style="color: #58a6ff; font-weight: bold;">`
-	case "38;5;240m":
-		return `<span style="color: #7d8590;">`
-	case "1;38;5;255m":
-		return `<span style="color: #ffffff; font-weight: bold;">`
-	case "38;5;246m":
-		return `<span style="color: #8b949e;">`
-	case "38;5;244m":
-		return `<span style="color: #6e7681;">`
-	case "1;38;5;255;48;5;240m":
-		return `<span style="color: #ffffff; background: #7d8590; font-weight: bold;">`
-	case "3;38;5;244m":
-		return `<span style="color: #6e7681; font-style: italic;">`
-	default:
-		// Skip unknown sequences (cursor movements, clears, etc.)
-		return ""
+// Report theme defaults used where a cell relies on the terminal's own colors
+var (
+	htmlDefaultForeground = color.RGBA{0xe6, 0xed, 0xf3, 0xff}
+	htmlDefaultBackground = color.RGBA{0x0d, 0x11, 0x17, 0xff}
+)
+
+// htmlThemeColors maps the 256-color palette entries the REPL styles use onto
+// the report theme, so reports keep their look rather than raw xterm values
+var htmlThemeColors = map[color.RGBA]string{
+	ansi256(39):  "#58a6ff", // accent blue
+	ansi256(240): "#7d8590", // muted gray
+	ansi256(244): "#6e7681", // comment gray
+	ansi256(246): "#8b949e", // secondary gray
+	ansi256(255): "#ffffff", // bright white
+}
+
+// htmlColor formats a cell color for the report, preferring the theme's shade
+func htmlColor(c color.RGBA) string {
+	if themed, ok := htmlThemeColors[c]; ok {
+		return themed
 	}
+	return hexColor(c)
+}
+
+// styleCSS converts cell attributes to an inline CSS declaration list
+func styleCSS(style CellStyle) string {
+	var css []string
+
+	fg, bg := style.Foreground, style.Background
+	if style.Reverse {
+		fg, bg = CellColor{RGBA: htmlDefaultBackground, Set: true}, CellColor{RGBA: htmlDefaultForeground, Set: true}
+		if style.Background.Set {
+			fg = style.Background
+		}
+		if style.Foreground.Set {
+			bg = style.Foreground
+		}
+	}
+
+	if fg.Set {
+		css = append(css, "color: "+htmlColor(fg.RGBA)+";")
+	}
+	if bg.Set {
+		css = append(css, "background: "+htmlColor(bg.RGBA)+";")
+	}
+	if style.Bold {
+		css = append(css, "font-weight: bold;")
+	}
+	if style.Dim {
+		css = append(css, "opacity: 0.6;")
+	}
+	if style.Italic {
+		css = append(css, "font-style: italic;")
+	}
+	if style.Underline {
+		css = append(css, "text-decoration: underline;")
+	}
+	return strings.Join(css, " ")
+}
+
+// hexColor formats a color as a CSS hex string
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
 }
 
 // escapeForHTML escapes ANSI content for safe embedding in HTML attributes
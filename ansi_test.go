@@ -73,26 +73,46 @@ func TestConvertANSIToHTML_Colors(t *testing.T) {
 		expected := `<span style="color: #6e7681; font-style: italic;">Italic comment</span>`
 		assert.Equal(t, expected, result)
 	})
+
+	t.Run("Themed background", func(t *testing.T) {
+		input := "\x1b[1;38;5;255;48;5;240mHeader\x1b[0m"
+		result := convertANSIToHTML(input)
+		expected := `<span style="color: #ffffff; background: #7d8590; font-weight: bold;">Header</span>`
+		assert.Equal(t, expected, result)
+	})
 }
 
-// TestConvertANSIToHTML_CursorMovement tests cursor sequence removal
+// TestConvertANSIToHTML_CursorMovement tests that cursor sequences are applied like a terminal
 func TestConvertANSIToHTML_CursorMovement(t *testing.T) {
-	t.Run("Remove cursor movements", func(t *testing.T) {
+	t.Run("Cursor movements reposition text", func(t *testing.T) {
+		// Single-line output: up/down are clamped, right skips a cell, left overwrites
 		input := "Text\x1b[AUp\x1b[BDown\x1b[CRight\x1b[DLeft"
 		result := convertANSIToHTML(input)
-		assert.Equal(t, "TextUpDownRightLeft", result)
+		assert.Equal(t, "TextUpDown RighLeft", result)
 	})
 
-	t.Run("Remove clear sequences", func(t *testing.T) {
+	t.Run("Clear sequences erase text", func(t *testing.T) {
 		input := "Before\x1b[JClear\x1b[2KLine\x1b[HHome"
 		result := convertANSIToHTML(input)
-		assert.Equal(t, "BeforeClearLineHome", result)
+		assert.Equal(t, "Home       Line", result)
 	})
 
-	t.Run("Remove carriage returns", func(t *testing.T) {
+	t.Run("Carriage returns overwrite the line", func(t *testing.T) {
 		input := "Text\rwith\rcarriage\rreturns"
 		result := convertANSIToHTML(input)
-		assert.Equal(t, "Textwithcarriagereturns", result)
+		assert.Equal(t, "returnse", result)
+	})
+
+	t.Run("Renderer repaint shows final frame", func(t *testing.T) {
+		// BubbleTea's renderer moves up and repaints lines in place
+		input := "first\r\nframe\x1b[1A\rsecond\x1b[K\r\nframe!\x1b[K"
+		result := convertANSIToHTML(input)
+		assert.Equal(t, "second<br>frame!", result)
+	})
+
+	t.Run("Text is HTML escaped", func(t *testing.T) {
+		result := convertANSIToHTML("<b>&</b>")
+		assert.Equal(t, "&lt;b&gt;&amp;&lt;/b&gt;", result)
 	})
 }
 
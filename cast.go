@@ -0,0 +1,171 @@
+package steadicam
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TerminalOutput is one write BubbleTea's renderer made to the stage's
+// terminal, or a resize of that terminal
+type TerminalOutput struct {
+	At     time.Duration // Since the stage started
+	Data   string        // Bytes written, escape sequences included
+	Width  int           // New terminal width, for resizes
+	Height int           // New terminal height, for resizes
+}
+
+// IsResize reports whether the entry records a resize rather than output
+func (o TerminalOutput) IsResize() bool {
+	return o.Width > 0 && o.Height > 0
+}
+
+// outputLog sits between the renderer and the virtual terminal, keeping a
+// timestamped copy of everything drawn
+type outputLog struct {
+	mu      sync.Mutex
+	start   time.Time
+	width   int // Terminal size when logging started
+	height  int
+	entries []TerminalOutput
+	screen  *Screen
+}
+
+// newOutputLog starts logging output on its way to screen
+func newOutputLog(screen *Screen) *outputLog {
+	width, height := screen.Size()
+	return &outputLog{start: time.Now(), width: width, height: height, screen: screen}
+}
+
+// Write logs renderer output and forwards it to the screen
+func (l *outputLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	l.entries = append(l.entries, TerminalOutput{At: time.Since(l.start), Data: string(p)})
+	l.mu.Unlock()
+	return l.screen.Write(p)
+}
+
+// resize logs a change of terminal size
+func (l *outputLog) resize(cols, rows int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, TerminalOutput{At: time.Since(l.start), Width: cols, Height: rows})
+}
+
+// output returns a copy of the log
+func (l *outputLog) output() []TerminalOutput {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]TerminalOutput(nil), l.entries...)
+}
+
+// castHeader is the first line of an asciicast v2 file
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Duration  float64           `json:"duration,omitempty"`
+	Env       map[string]string `json:"env"`
+}
+
+// SaveCast writes the stage as an asciinema v2 recording to path, creating
+// its directory if needed. See WriteCast.
+//
+// Example:
+//
+//	result := director.Stop()
+//	if !result.Success {
+//		result.SaveCast("failures/" + t.Name() + ".cast") // asciinema play failures/...
+//	}
+func (r *StageResult) SaveCast(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.WriteCast(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteCast writes the stage as an asciinema v2 recording, playable with
+// asciinema, asciinema-player or any other asciicast tool.
+//
+// The recording is the renderer's raw output with its original timing,
+// at the stage's terminal size, with resizes as "r" events. Results without
+// raw output, such as quiet benchmark stages, fall back to the captured
+// snapshots, each redrawn as a full screen.
+func (r *StageResult) WriteCast(w io.Writer) error {
+	width, height := r.Width, r.Height
+	if width < 1 || height < 1 {
+		width, height = defaultTerminalWidth, defaultTerminalHeight
+	}
+
+	events := r.Output
+	if len(events) == 0 {
+		events = snapshotOutput(r.Snapshots)
+	}
+
+	header := castHeader{
+		Version: 2,
+		Width:   width,
+		Height:  height,
+		Env:     map[string]string{"TERM": "xterm-256color"},
+	}
+	if !r.StartedAt.IsZero() {
+		header.Timestamp = r.StartedAt.Unix()
+	}
+	if len(events) > 0 {
+		header.Duration = castSeconds(events[len(events)-1].At)
+	}
+
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(header); err != nil {
+		return err
+	}
+	for _, event := range events {
+		line := []interface{}{castSeconds(event.At), "o", event.Data}
+		if event.IsResize() {
+			line = []interface{}{castSeconds(event.At), "r", fmt.Sprintf("%dx%d", event.Width, event.Height)}
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
+
+// snapshotOutput turns snapshots into full-screen redraws timed from the first one
+func snapshotOutput(snapshots []StageSnapshot) []TerminalOutput {
+	var output []TerminalOutput
+	for _, snapshot := range snapshots {
+		at := snapshot.Timestamp.Sub(snapshots[0].Timestamp)
+		if at < 0 {
+			at = 0
+		}
+		output = append(output, TerminalOutput{
+			At:   at,
+			Data: "\x1b[H\x1b[2J" + strings.ReplaceAll(snapshot.View, "\n", "\r\n"),
+		})
+	}
+	return output
+}
+
+// castSeconds converts a duration to asciicast's seconds, to the microsecond
+func castSeconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1e6
+}
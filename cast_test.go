@@ -0,0 +1,100 @@
+package steadicam
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readCast splits an asciicast v2 file into its header and events
+func readCast(t *testing.T, data []byte) (map[string]interface{}, [][]interface{}) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+
+	require.True(t, scanner.Scan())
+	var header map[string]interface{}
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+
+	var events [][]interface{}
+	for scanner.Scan() {
+		var event []interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		require.Len(t, event, 3)
+		events = append(events, event)
+	}
+	return header, events
+}
+
+// TestStageResult_WriteCast tests exporting the renderer output as an asciicast
+func TestStageResult_WriteCast(t *testing.T) {
+	director := NewStageDirectorWithConfig(t, &mockREPLForInteractions{mode: "ready"}, testAdapterConfig).
+		WithInitialSize(60, 15)
+	director.Start().Type("cast").Resize(40, 12).Type("!")
+	result := director.Stop()
+
+	require.NotEmpty(t, result.Output)
+	path := filepath.Join(t.TempDir(), "casts", "stage.cast")
+	require.NoError(t, result.SaveCast(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	header, events := readCast(t, data)
+	assert.Equal(t, 2.0, header["version"])
+	assert.Equal(t, 60.0, header["width"])
+	assert.Equal(t, 15.0, header["height"])
+	assert.Equal(t, float64(result.StartedAt.Unix()), header["timestamp"])
+	assert.Equal(t, map[string]interface{}{"TERM": "xterm-256color"}, header["env"])
+
+	var output strings.Builder
+	var resizes []string
+	last := 0.0
+	for _, event := range events {
+		at := event[0].(float64)
+		assert.GreaterOrEqual(t, at, last, "events must be in time order")
+		last = at
+
+		switch event[1] {
+		case "o":
+			output.WriteString(event[2].(string))
+		case "r":
+			resizes = append(resizes, event[2].(string))
+		default:
+			t.Errorf("unexpected event type %v", event[1])
+		}
+	}
+	assert.Equal(t, []string{"40x12"}, resizes)
+
+	// Playing the output back draws what the stage ended on
+	screen := NewScreen(60, 15)
+	screen.WriteString(output.String())
+	assert.Contains(t, screen.String(), "Mock REPL: cast!")
+}
+
+// TestStageResult_WriteCastFromSnapshots tests the fallback for results without raw output
+func TestStageResult_WriteCastFromSnapshots(t *testing.T) {
+	start := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	result := &StageResult{Snapshots: []StageSnapshot{
+		{Timestamp: start, View: "first"},
+		{Timestamp: start.Add(1500 * time.Millisecond), View: "second\nline"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, result.WriteCast(&buf))
+
+	header, events := readCast(t, buf.Bytes())
+	assert.Equal(t, 80.0, header["width"])
+	assert.Equal(t, 24.0, header["height"])
+	assert.Equal(t, 1.5, header["duration"])
+	assert.Equal(t, [][]interface{}{
+		{0.0, "o", "\x1b[H\x1b[2Jfirst"},
+		{1.5, "o", "\x1b[H\x1b[2Jsecond\r\nline"},
+	}, events)
+}
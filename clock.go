@@ -0,0 +1,235 @@
+package steadicam
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Clock is the source of time for models that tick, animate or debounce.
+//
+// BubbleTea's tea.Tick and tea.Every wait on the wall clock inside their
+// commands, where no test can reach them. Models that take a Clock and call
+// its Tick and Every instead run on RealClock in production and on a
+// FakeClock under a stage, where time only moves when the test says so.
+//
+// Example:
+//
+//	type Spinner struct{ clock steadicam.Clock; frame int }
+//
+//	func (s Spinner) Init() tea.Cmd {
+//		return s.clock.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return spinMsg{} })
+//	}
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// Tick behaves like tea.Tick: one message after duration d
+	Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd
+	// Every behaves like tea.Every: one message at the next multiple of d
+	Every(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd
+}
+
+// RealClock is the wall clock, delegating to BubbleTea's own timers
+type RealClock struct{}
+
+// Now returns time.Now()
+func (RealClock) Now() time.Time { return time.Now() }
+
+// Tick returns tea.Tick(d, fn)
+func (RealClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd { return tea.Tick(d, fn) }
+
+// Every returns tea.Every(d, fn)
+func (RealClock) Every(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd { return tea.Every(d, fn) }
+
+// FakeClock is a virtual clock whose timers fire only when time is advanced.
+//
+// Timers are scheduled when Tick or Every is called, which happens inside the
+// model's Update, so by the time the stage observes an update its timers are
+// already pending. The returned commands produce no message themselves:
+// the clock delivers each timer's message when it fires.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	seq    int
+}
+
+// fakeTimer is a pending Tick or Every
+type fakeTimer struct {
+	at  time.Time
+	fn  func(time.Time) tea.Msg
+	seq int // Keeps timers due at the same instant in scheduling order
+}
+
+// NewFakeClock creates a virtual clock starting at start. A zero start uses a
+// fixed date so that formatted times are reproducible.
+func NewFakeClock(start time.Time) *FakeClock {
+	if start.IsZero() {
+		start = time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return &FakeClock{now: start}
+}
+
+// Now returns the virtual time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Tick schedules fn to run d after the current virtual time
+func (c *FakeClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(c.now.Add(d), fn)
+	return fakeTimerCmd
+}
+
+// Every schedules fn at the next multiple of d, aligned like tea.Every
+func (c *FakeClock) Every(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(c.now.Truncate(d).Add(d), fn)
+	return fakeTimerCmd
+}
+
+// Pending returns how many timers have not fired yet
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// Advance moves virtual time forward by d and returns the messages of the
+// timers that fired, in order. Use it to drive a model's Update by hand;
+// under a stage, StageDirector.AdvanceTime also delivers the messages and
+// fires timers that their updates schedule along the way.
+func (c *FakeClock) Advance(d time.Duration) []tea.Msg {
+	target := c.Now().Add(d)
+
+	var msgs []tea.Msg
+	for {
+		msg, ok := c.fireNext(target)
+		if !ok {
+			break
+		}
+		if msg != nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// fireNext fires the earliest timer due by target, moving the clock to it.
+// When none is due, the clock moves to target and ok is false.
+func (c *FakeClock) fireNext(target time.Time) (msg tea.Msg, ok bool) {
+	c.mu.Lock()
+	if len(c.timers) == 0 || c.timers[0].at.After(target) {
+		if target.After(c.now) {
+			c.now = target
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	timer := c.timers[0]
+	c.timers = c.timers[1:]
+	if timer.at.After(c.now) {
+		c.now = timer.at
+	}
+	c.mu.Unlock()
+
+	// Run fn unlocked: it may read the clock
+	return timer.fn(timer.at), true
+}
+
+// schedule adds a timer, keeping timers ordered by due time; callers hold mu
+func (c *FakeClock) schedule(at time.Time, fn func(time.Time) tea.Msg) {
+	c.seq++
+	c.timers = append(c.timers, &fakeTimer{at: at, fn: fn, seq: c.seq})
+	sort.SliceStable(c.timers, func(i, j int) bool {
+		if c.timers[i].at.Equal(c.timers[j].at) {
+			return c.timers[i].seq < c.timers[j].seq
+		}
+		return c.timers[i].at.Before(c.timers[j].at)
+	})
+}
+
+// fakeTimerCmd is the command FakeClock hands back; the clock delivers the message
+func fakeTimerCmd() tea.Msg { return nil }
+
+// WithClock runs the stage on a virtual clock. Give the model the same clock,
+// then move time with AdvanceTime; Wait advances the clock instead of sleeping.
+// Timers the model still builds with tea.Tick or tea.Every are held back
+// and reported as WALL_CLOCK_TIMER trips rather than left to fire in real time.
+// IMPORTANT: Must be called before Start() so no timer is missed.
+//
+// Example:
+//
+//	clock := steadicam.NewFakeClock(time.Time{})
+//	director := steadicam.NewStageDirectorForModel(t, NewSearch(clock)).WithClock(clock).Start()
+//	director.Type("go").AdvanceTime(300 * time.Millisecond).AssertViewContains("searching")
+func (d *StageDirector) WithClock(clock *FakeClock) *StageDirector {
+	if d.started {
+		d.recordTrip(newStageTrip("CLOCK_AFTER_START", "WithClock must be called before Start; timers may already be running on the wall clock", nil))
+		return d
+	}
+	d.clock = clock
+	return d
+}
+
+// AdvanceTime moves the virtual clock forward, firing due timers in order.
+//
+// Each timer's message is delivered and processed before the next timer
+// fires, and timers scheduled by those updates fire too if they fall due
+// within the window, so a spinner advanced by a second steps through every
+// frame. Requires WithClock.
+func (d *StageDirector) AdvanceTime(duration time.Duration) *StageDirector {
+	if d.clock == nil {
+		trip := newStageTrip("NO_FAKE_CLOCK", "AdvanceTime requires a virtual clock; call WithClock before Start", map[string]interface{}{
+			"duration": duration.String(),
+		})
+		d.recordTrip(trip)
+		return d
+	}
+
+	fired := d.advanceClock(duration)
+	d.recordStageAction("advance_time", fmt.Sprintf("%v fired=%d", duration, fired))
+	return d
+}
+
+// advanceClock fires the virtual timers due within duration, returning how many fired
+func (d *StageDirector) advanceClock(duration time.Duration) int {
+	target := d.clock.Now().Add(duration)
+	fired := 0
+	for {
+		msg, ok := d.clock.fireNext(target)
+		if !ok {
+			return fired
+		}
+		fired++
+		if msg != nil {
+			d.deliverMessage(msg)
+		}
+	}
+}
+
+// deliverMessage sends a message and waits for the model to process it,
+// even in lossy mode where the view might not change
+func (d *StageDirector) deliverMessage(msg tea.Msg) {
+	if d.program == nil {
+		return
+	}
+	if !shouldStage(msg) {
+		d.sendMessage(msg)
+		return
+	}
+
+	d.updateMu.Lock()
+	defer d.updateMu.Unlock()
+	d.sendStagedMessage(msg)
+	d.captureSnapshot("interaction")
+}
@@ -0,0 +1,143 @@
+package steadicam
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// debounceMsg fires once typing has paused
+type debounceMsg struct{ version int }
+
+// spinMsg advances the spinner
+type spinMsg struct{}
+
+// debounceModel searches 300ms after the last keystroke while a spinner turns
+type debounceModel struct {
+	clock    Clock
+	query    string
+	version  int
+	searched string
+	frame    int
+}
+
+func (m debounceModel) Init() tea.Cmd {
+	return m.clock.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return spinMsg{} })
+}
+
+func (m debounceModel) View() string {
+	return fmt.Sprintf("query=%s searched=%s frame=%d", m.query, m.searched, m.frame)
+}
+
+func (m debounceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.query += string(msg.Runes)
+		m.version++
+		version := m.version
+		return m, m.clock.Tick(300*time.Millisecond, func(time.Time) tea.Msg { return debounceMsg{version} })
+	case debounceMsg:
+		if msg.version == m.version {
+			m.searched = m.query
+		}
+	case spinMsg:
+		m.frame++
+		return m, m.clock.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return spinMsg{} })
+	}
+	return m, nil
+}
+
+// TestStageDirector_VirtualClock tests debouncing and animation on a fake clock
+func TestStageDirector_VirtualClock(t *testing.T) {
+	clock := NewFakeClock(time.Time{})
+	director := NewStageDirectorForModelWithConfig(t, debounceModel{clock: clock}, testAdapterConfig).WithClock(clock)
+	defer director.Stop()
+
+	start := time.Now()
+	director.Start().Type("g").AdvanceTime(200 * time.Millisecond).Type("o")
+	director.AssertViewContains("searched= frame=2")
+
+	// The first keystroke's debounce is superseded by the second
+	director.AdvanceTime(299 * time.Millisecond).AssertViewContains("searched= frame=4")
+	director.Wait(time.Millisecond).AssertViewContains("query=go searched=go frame=5")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	assert.Less(t, time.Since(start), time.Second, "virtual time does not sleep")
+
+	assert.Equal(t, time.Date(2001, time.January, 1, 0, 0, 0, 500_000_000, time.UTC), clock.Now())
+	assert.Equal(t, 1, clock.Pending(), "only the next spinner frame is scheduled")
+}
+
+// TestFakeClock tests timer ordering without a stage
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2001, time.January, 1, 0, 0, 0, 250_000_000, time.UTC))
+	at := func(label string) func(time.Time) tea.Msg {
+		return func(now time.Time) tea.Msg { return fmt.Sprintf("%s@%s", label, now.Format("05.000")) }
+	}
+
+	clock.Tick(time.Second, at("tick"))
+	clock.Every(time.Second, at("every")) // Aligned to the next whole second
+	clock.Tick(750*time.Millisecond, at("same"))
+
+	assert.Empty(t, clock.Advance(500*time.Millisecond))
+	assert.Equal(t, []tea.Msg{"every@01.000", "same@01.000", "tick@01.250"}, clock.Advance(time.Second))
+	assert.Equal(t, 0, clock.Pending())
+
+	director := NewStageDirectorForModelWithConfig(t, burstModel{}, testAdapterConfig)
+	director.AdvanceTime(time.Second)
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "NO_FAKE_CLOCK", director.lastTrip.Type)
+}
+
+// wallClockModel starts a real tea.Tick, as models not written for a Clock do
+type wallClockModel struct{ ticked bool }
+
+func (m wallClockModel) Init() tea.Cmd {
+	return tea.Tick(10*time.Millisecond, func(time.Time) tea.Msg { return spinMsg{} })
+}
+
+func (m wallClockModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(spinMsg); ok {
+		m.ticked = true
+	}
+	return m, nil
+}
+
+func (m wallClockModel) View() string { return fmt.Sprintf("ticked: %v", m.ticked) }
+
+// TestStageDirector_WallClockTimer tests that tea.Tick is held back on a virtual clock stage
+func TestStageDirector_WallClockTimer(t *testing.T) {
+	clock := NewFakeClock(time.Time{})
+	director := NewStageDirectorForModelWithConfig(&recordingTB{TB: t}, wallClockModel{}, testAdapterConfig).WithClock(clock)
+	defer director.Stop()
+
+	director.Start()
+	require.Eventually(t, func() bool { return len(director.IssuedCommands()) == 1 }, time.Second, time.Millisecond)
+
+	// The trip is recorded by the next step the test takes
+	director.AssertViewContains("ticked: false")
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "WALL_CLOCK_TIMER", director.lastTrip.Type)
+	assert.Contains(t, director.lastTrip.Context["command"], "bubbletea.Tick")
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "ticked: false", director.getCurrentView(), "the real timer never fired")
+
+	issued := director.IssuedCommands()
+	require.Len(t, issued, 1)
+	assert.True(t, issued[0].Stubbed)
+}
+
+// TestStageDirector_ClockAfterStart tests that a late WithClock trips
+func TestStageDirector_ClockAfterStart(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, burstModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().WithClock(NewFakeClock(time.Time{}))
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "CLOCK_AFTER_START", director.lastTrip.Type)
+	assert.Nil(t, director.clock)
+}
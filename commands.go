@@ -0,0 +1,285 @@
+package steadicam
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// IssuedCommand records a tea.Cmd returned by the model's Init or Update.
+//
+// Commands are plain functions, so they are identified by Name: the Go
+// function name, such as "example.com/app.fetchResults". Closures are named
+// after the function that built them ("example.com/app.search.func1"), which
+// keeps constructor names recognizable. Commands inside a tea.Batch are
+// recorded individually once the batch runs.
+type IssuedCommand struct {
+	Name     string        // Function name of the command
+	Cause    tea.Msg       // Message whose Update returned it, nil for Init
+	IssuedAt time.Time     // When Update returned it
+	Stubbed  bool          // Whether a stub replaced the real command
+	Delay    time.Duration // Delay applied before it ran
+	Done     bool          // Whether it has finished running
+	Result   tea.Msg       // Message it produced, once Done
+}
+
+// String describes the command for trip reports
+func (c IssuedCommand) String() string {
+	cause := "init"
+	if c.Cause != nil {
+		cause = fmt.Sprintf("%T", c.Cause)
+	}
+	return fmt.Sprintf("%s (from %s)", c.Name, cause)
+}
+
+// CommandNamed matches commands whose function name contains name, so
+// CommandNamed("fetchResults") matches both fetchResults and the closures it returns
+func CommandNamed(name string) func(IssuedCommand) bool {
+	return func(cmd IssuedCommand) bool {
+		return strings.Contains(cmd.Name, name)
+	}
+}
+
+// commandStub replaces matching commands with a canned result
+type commandStub struct {
+	match  func(IssuedCommand) bool
+	result tea.Msg
+}
+
+// commandDelay postpones matching commands
+type commandDelay struct {
+	match func(IssuedCommand) bool
+	delay time.Duration
+}
+
+// commandLog holds the commands a stage has seen and the rules applied to them
+type commandLog struct {
+	mu      sync.Mutex
+	issued  []*IssuedCommand
+	stubs   []commandStub
+	delays  []commandDelay
+	pending int64     // atomic count of commands issued but not yet finished
+	doneAt  time.Time // When a command last finished running
+
+	wallClock []*IssuedCommand // tea.Tick/tea.Every commands held back from a virtual clock stage
+	reported  int              // How many of them have been reported as trips
+}
+
+// wallClockTimers names the closures tea.Tick and tea.Every return
+var wallClockTimers = []string{
+	commandName(tea.Tick) + ".",
+	commandName(tea.Every) + ".",
+}
+
+// isWallClockTimer reports whether a command was built by tea.Tick or tea.Every
+func isWallClockTimer(name string) bool {
+	for _, prefix := range wallClockTimers {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastDone returns when a command last finished running
+func (l *commandLog) lastDone() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.doneAt
+}
+
+// Send delivers any message to the model as if a command had produced it,
+// for driving models with custom messages such as search results or ticks.
+// It waits for the model to react like the other interactions do.
+//
+// Example:
+//
+//	director.Send(searchResultsMsg{items: fixtures}).WaitForText("3 results")
+func (d *StageDirector) Send(msg tea.Msg) *StageDirector {
+	if msg == nil {
+		return d
+	}
+	d.sendMessage(msg)
+	d.recordStageAction("send", fmt.Sprintf("%T", msg))
+	return d
+}
+
+// StubCommand stops matching commands from running and delivers result in
+// their place, so tests can fake HTTP calls, database queries and other side
+// effects. A nil result swallows the command entirely. The first matching stub
+// wins; stubs apply to commands issued after the call.
+//
+// Example:
+//
+//	director.StubCommand(steadicam.CommandNamed("fetchResults"), resultsMsg{items: fixtures})
+func (d *StageDirector) StubCommand(match func(IssuedCommand) bool, result tea.Msg) *StageDirector {
+	d.commands.mu.Lock()
+	defer d.commands.mu.Unlock()
+	d.commands.stubs = append(d.commands.stubs, commandStub{match: match, result: result})
+	return d
+}
+
+// DelayCommands holds matching commands back for a duration before they run,
+// to exercise loading states and slow responses without a slow backend
+func (d *StageDirector) DelayCommands(match func(IssuedCommand) bool, delay time.Duration) *StageDirector {
+	d.commands.mu.Lock()
+	defer d.commands.mu.Unlock()
+	d.commands.delays = append(d.commands.delays, commandDelay{match: match, delay: delay})
+	return d
+}
+
+// IssuedCommands returns every command the model has issued so far, in order
+func (d *StageDirector) IssuedCommands() []IssuedCommand {
+	d.commands.mu.Lock()
+	defer d.commands.mu.Unlock()
+
+	issued := make([]IssuedCommand, len(d.commands.issued))
+	for i, cmd := range d.commands.issued {
+		issued[i] = *cmd
+	}
+	return issued
+}
+
+// PendingCommands returns how many issued commands have not finished running
+func (d *StageDirector) PendingCommands() int {
+	return int(atomic.LoadInt64(&d.commands.pending))
+}
+
+// AssertCommandIssued verifies that the model has issued a matching command
+//
+// Example:
+//
+//	director.PressEnter().AssertCommandIssued(steadicam.CommandNamed("fetchResults"))
+func (d *StageDirector) AssertCommandIssued(match func(IssuedCommand) bool) *StageDirector {
+	issued := d.IssuedCommands()
+	for _, cmd := range issued {
+		if match(cmd) {
+			d.recordStageAction("assertion", "command_issued="+cmd.Name)
+			return d
+		}
+	}
+
+	names := make([]string, len(issued))
+	for i, cmd := range issued {
+		names[i] = cmd.String()
+	}
+	trip := newStageTrip("assertion", "No matching command was issued", map[string]interface{}{
+		"issued_commands": names,
+	})
+	d.recordTrip(trip)
+	return d
+}
+
+// interceptCommand records a command and wraps it so stubs, delays and
+// completion tracking apply when BubbleTea runs it
+func (d *StageDirector) interceptCommand(cmd tea.Cmd, cause tea.Msg) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+
+	issued := &IssuedCommand{
+		Name:     commandName(cmd),
+		Cause:    cause,
+		IssuedAt: time.Now(),
+	}
+
+	d.commands.mu.Lock()
+	var stub *commandStub
+	for i := range d.commands.stubs {
+		if d.commands.stubs[i].match(*issued) {
+			stub = &d.commands.stubs[i]
+			break
+		}
+	}
+	for _, rule := range d.commands.delays {
+		if rule.match(*issued) {
+			issued.Delay = rule.delay
+			break
+		}
+	}
+	issued.Stubbed = stub != nil
+
+	// A real timer would fire on the wall clock behind the virtual one's back.
+	// Its duration can't be read back out of the command, so hold it and trip.
+	wallClock := d.clock != nil && stub == nil && isWallClockTimer(issued.Name)
+	if wallClock {
+		issued.Stubbed = true
+		d.commands.wallClock = append(d.commands.wallClock, issued)
+	}
+	d.commands.issued = append(d.commands.issued, issued)
+	d.commands.mu.Unlock()
+	atomic.AddInt64(&d.commands.pending, 1)
+
+	return func() tea.Msg {
+		var result tea.Msg
+		defer func() {
+			d.commands.mu.Lock()
+			issued.Done, issued.Result = true, result
+			d.commands.doneAt = time.Now()
+			d.commands.mu.Unlock()
+			atomic.AddInt64(&d.commands.pending, -1)
+			d.notifyStateChange()
+		}()
+
+		if issued.Delay > 0 {
+			timer := time.NewTimer(issued.Delay)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-d.ctx.Done():
+				return nil
+			}
+		}
+
+		if wallClock {
+			return nil
+		}
+		if stub != nil {
+			result = stub.result
+		} else {
+			result = cmd()
+		}
+
+		// Batched commands run separately, so intercept each of them too
+		if batch, ok := result.(tea.BatchMsg); ok {
+			wrapped := make(tea.BatchMsg, len(batch))
+			for i, inner := range batch {
+				wrapped[i] = d.interceptCommand(inner, cause)
+			}
+			result = wrapped
+		}
+		return result
+	}
+}
+
+// reportWallClockTimers records a trip for each tea.Tick or tea.Every command
+// held back since the last report. Commands are intercepted on BubbleTea's
+// goroutine, so the trips are recorded here, on the test's.
+func (d *StageDirector) reportWallClockTimers() {
+	d.commands.mu.Lock()
+	held := d.commands.wallClock[d.commands.reported:]
+	d.commands.reported = len(d.commands.wallClock)
+	d.commands.mu.Unlock()
+
+	for _, cmd := range held {
+		trip := newStageTrip("WALL_CLOCK_TIMER", "Model issued a wall-clock timer on a virtual clock stage: "+cmd.String()+
+			"; build timers with the steadicam.Clock given to WithClock instead of tea.Tick or tea.Every", map[string]interface{}{
+			"command": cmd.Name,
+		})
+		d.recordTrip(trip)
+	}
+}
+
+// commandName returns the Go function name of a command
+func commandName(cmd interface{}) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(cmd).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "unknown"
+}
@@ -0,0 +1,88 @@
+package steadicam
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resultsMsg carries search results back to searchModel
+type resultsMsg struct {
+	source string
+	items  []string
+}
+
+// loggedMsg is produced by logQuery
+type loggedMsg struct{}
+
+// fetchResults stands in for a command with side effects, such as an HTTP call
+func fetchResults(query string) tea.Cmd {
+	return func() tea.Msg {
+		return resultsMsg{source: "backend", items: []string{query + " 1", query + " 2"}}
+	}
+}
+
+// logQuery is batched alongside fetchResults
+func logQuery() tea.Msg { return loggedMsg{} }
+
+// searchModel issues commands on Enter and shows their results
+type searchModel struct {
+	status string
+}
+
+func (m searchModel) Init() tea.Cmd { return nil }
+func (m searchModel) View() string  { return "status: " + m.status }
+
+func (m searchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyEnter {
+			m.status = "loading"
+			return m, tea.Batch(fetchResults("go"), logQuery)
+		}
+	case resultsMsg:
+		m.status = msg.source + ": " + strings.Join(msg.items, ", ")
+	}
+	return m, nil
+}
+
+// TestStageDirector_Commands tests recording, stubbing and delaying model commands
+func TestStageDirector_Commands(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, searchModel{status: "idle"}, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().PressEnter().WaitForText("backend: go 1, go 2")
+	director.AssertCommandIssued(CommandNamed("fetchResults")).
+		AssertCommandIssued(CommandNamed("logQuery"))
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+
+	issued := director.IssuedCommands()
+	require.Len(t, issued, 3, "the batch and both commands inside it")
+	assert.Contains(t, issued[1].Name, "fetchResults.func1")
+	assert.Equal(t, tea.KeyMsg{Type: tea.KeyEnter}, issued[1].Cause)
+	assert.Equal(t, resultsMsg{source: "backend", items: []string{"go 1", "go 2"}}, issued[1].Result)
+
+	// Stubbed commands never run; delayed ones show their loading state first
+	director.StubCommand(CommandNamed("fetchResults"), resultsMsg{source: "stub", items: []string{"fixture"}}).
+		DelayCommands(CommandNamed("fetchResults"), 300*time.Millisecond)
+	director.PressEnter().AssertViewContains("status: loading")
+	assert.Eventually(t, func() bool { return director.PendingCommands() == 1 }, 200*time.Millisecond, time.Millisecond,
+		"only the delayed command is still running")
+
+	director.WaitForText("stub: fixture")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	assert.Eventually(t, func() bool { return director.PendingCommands() == 0 }, time.Second, time.Millisecond)
+	assert.True(t, director.IssuedCommands()[4].Stubbed)
+
+	// Custom messages can be sent directly
+	director.Send(resultsMsg{source: "sent", items: []string{"by hand"}}).WaitForText("sent: by hand")
+	assert.False(t, director.HasFailed(), director.getErrorMessage())
+
+	director.AssertCommandIssued(CommandNamed("neverIssued"))
+	require.NotNil(t, director.lastTrip)
+	assert.Contains(t, director.lastTrip.Context["issued_commands"], issued[1].String())
+}
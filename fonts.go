@@ -0,0 +1,168 @@
+package steadicam
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"os"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Bitmap font cell, used when no font file, size or scale is configured
+const (
+	bitmapCellWidth  = 8
+	bitmapCellHeight = 16
+)
+
+// defaultFontSize is the scalable font size when only Scale is configured,
+// matching the height of the bitmap font
+const defaultFontSize = 13
+
+// fontSet is the loaded lens kit: the face glyphs are drawn with, the cell it
+// implies, and the family name SVG captures ask viewers for
+type fontSet struct {
+	face       font.Face
+	family     string
+	charWidth  int
+	charHeight int
+}
+
+// loadFonts prepares the faces a config asks for. The built-in bitmap font
+// keeps its 8x16 cell when nothing is configured; otherwise FontFile (or the
+// embedded Go Mono) is rasterized at FontSize x Scale, with FallbackFonts
+// consulted in order for glyphs it lacks, and cells are measured from the face.
+func loadFonts(config Config) (*fontSet, error) {
+	scale := config.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+	if config.FontFile == "" && config.FontSize <= 0 && scale == 1 && len(config.FallbackFonts) == 0 {
+		return &fontSet{face: basicfont.Face7x13, charWidth: bitmapCellWidth, charHeight: bitmapCellHeight}, nil
+	}
+
+	size := float64(config.FontSize)
+	if size <= 0 {
+		size = defaultFontSize
+	}
+	options := &opentype.FaceOptions{Size: size * scale, DPI: 72, Hinting: font.HintingFull}
+
+	primary := gomono.TTF
+	if config.FontFile != "" {
+		data, err := os.ReadFile(config.FontFile)
+		if err != nil {
+			return nil, err
+		}
+		primary = data
+	}
+	main, err := parseFont(primary)
+	if err != nil {
+		return nil, fmt.Errorf("font %s: %w", config.FontFile, err)
+	}
+
+	fonts := []*sfnt.Font{main}
+	for _, path := range config.FallbackFonts {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fallback, err := parseFont(data)
+		if err != nil {
+			return nil, fmt.Errorf("fallback font %s: %w", path, err)
+		}
+		fonts = append(fonts, fallback)
+	}
+
+	faces := make([]font.Face, 0, len(fonts))
+	for _, f := range fonts {
+		face, err := opentype.NewFace(f, options)
+		if err != nil {
+			return nil, err
+		}
+		faces = append(faces, face)
+	}
+
+	set := &fontSet{face: faces[0]}
+	if len(faces) > 1 {
+		set.face = &fallbackFace{faces: faces}
+	}
+	if family, err := main.Name(nil, sfnt.NameIDFamily); err == nil {
+		set.family = family
+	}
+
+	// Monospace faces share one advance; measure it on a full-width letter
+	advance, ok := faces[0].GlyphAdvance('M')
+	if !ok {
+		advance = fixed.I(int(math.Ceil(options.Size * 0.6)))
+	}
+	set.charWidth = advance.Ceil()
+	set.charHeight = faces[0].Metrics().Height.Ceil()
+	return set, nil
+}
+
+// parseFont parses a TrueType or OpenType font, taking the first font of a collection
+func parseFont(data []byte) (*sfnt.Font, error) {
+	f, err := opentype.Parse(data)
+	if err == nil {
+		return f, nil
+	}
+
+	collection, collectionErr := opentype.ParseCollection(data)
+	if collectionErr != nil {
+		return nil, err
+	}
+	return collection.Font(0)
+}
+
+// fallbackFace draws each rune with the first face that has a glyph for it,
+// so box-drawing, CJK and emoji glyphs missing from the main font still show
+type fallbackFace struct {
+	faces []font.Face
+}
+
+// faceFor picks the face that draws r, falling back to the main face's
+// missing-glyph box when no face has it
+func (f *fallbackFace) faceFor(r rune) font.Face {
+	for _, face := range f.faces {
+		if _, ok := face.GlyphAdvance(r); ok {
+			return face
+		}
+	}
+	return f.faces[0]
+}
+
+func (f *fallbackFace) Close() error {
+	for _, face := range f.faces {
+		face.Close()
+	}
+	return nil
+}
+
+func (f *fallbackFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+func (f *fallbackFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+func (f *fallbackFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+func (f *fallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if face := f.faceFor(r0); face == f.faceFor(r1) {
+		return face.Kern(r0, r1)
+	}
+	return 0
+}
+
+// Metrics are the main face's, which sets the cell
+func (f *fallbackFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
@@ -0,0 +1,143 @@
+package steadicam
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/opentype"
+)
+
+// inkIn reports whether any pixel in r differs from the background
+func inkIn(img *image.RGBA, r image.Rectangle, background color.RGBA) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.RGBAAt(x, y) != background {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestRenderingStage_Fonts tests that cell metrics follow the configured font
+func TestRenderingStage_Fonts(t *testing.T) {
+	fontFile := filepath.Join(t.TempDir(), "mono.ttf")
+	require.NoError(t, os.WriteFile(fontFile, gomono.TTF, 0644))
+
+	tests := []struct {
+		name          string
+		config        Config
+		width, height int
+		family        string
+	}{
+		{"bitmap default", Config{}, 8, 16, ""},
+		{"font size", Config{FontSize: 12}, 7, 14, "Go Mono"},
+		{"font file", Config{FontFile: fontFile, FontSize: 20}, 12, 24, "Go Mono"},
+		{"scale", Config{FontSize: 12, Scale: 2}, 14, 28, "Go Mono"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Width, tt.config.Height = 10, 2
+			rs := NewRenderingStage(tt.config)
+			require.NoError(t, rs.fontErr)
+			assert.Equal(t, tt.width, rs.charWidth)
+			assert.Equal(t, tt.height, rs.charHeight)
+			assert.Equal(t, tt.family, rs.fontFamily)
+
+			rs.RenderText("hi")
+			assert.Equal(t, image.Rect(0, 0, 10*tt.width, 2*tt.height), rs.renderImage().Bounds())
+		})
+	}
+}
+
+// TestRenderingStage_BoxDrawing tests that box-drawing lines join across cells
+func TestRenderingStage_BoxDrawing(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	rs := NewRenderingStage(Config{Width: 3, Height: 1, FontSize: 12, Background: black, Foreground: color.RGBA{255, 255, 255, 255}})
+	rs.RenderText("───")
+	img := rs.renderImage()
+
+	// Some pixel row is inked from the first cell's left edge to the last cell's right edge
+	var joined bool
+	for y := 0; y < rs.charHeight && !joined; y++ {
+		joined = true
+		for x := 0; x < 3*rs.charWidth; x++ {
+			if !inkIn(img, image.Rect(x, y, x+1, y+1), black) {
+				joined = false
+				break
+			}
+		}
+	}
+	assert.True(t, joined, "horizontal lines should be drawn edge to edge")
+}
+
+// TestFallbackFace tests that runes missing from the main face come from fallbacks
+func TestFallbackFace(t *testing.T) {
+	f, err := opentype.Parse(gomono.TTF)
+	require.NoError(t, err)
+	mono, err := opentype.NewFace(f, &opentype.FaceOptions{Size: 13, DPI: 72})
+	require.NoError(t, err)
+
+	face := &fallbackFace{faces: []font.Face{basicfont.Face7x13, mono}}
+	assert.Equal(t, basicfont.Face7x13, face.faceFor('a'))
+	assert.Equal(t, mono, face.faceFor('─'), "box drawing falls back to Go Mono")
+	assert.Equal(t, basicfont.Face7x13, face.faceFor('漢'), "glyphs no face has use the main face")
+	assert.Equal(t, basicfont.Face7x13.Metrics(), face.Metrics())
+
+	_, ok := face.GlyphAdvance('─')
+	assert.True(t, ok)
+}
+
+// TestRenderingStage_FallbackFonts tests loading fallback font files
+func TestRenderingStage_FallbackFonts(t *testing.T) {
+	dir := t.TempDir()
+	fallback := filepath.Join(dir, "fallback.ttf")
+	require.NoError(t, os.WriteFile(fallback, gomono.TTF, 0644))
+
+	rs := NewRenderingStage(Config{Width: 4, Height: 1, FontSize: 12, FallbackFonts: []string{fallback}})
+	require.NoError(t, rs.fontErr)
+	require.IsType(t, &fallbackFace{}, rs.font)
+	assert.Len(t, rs.font.(*fallbackFace).faces, 2)
+	assert.Equal(t, 7, rs.charWidth, "cells are measured on the main font")
+}
+
+// TestRenderingStage_BadFont tests that unloadable fonts fail captures
+func TestRenderingStage_BadFont(t *testing.T) {
+	dir := t.TempDir()
+	notAFont := filepath.Join(dir, "font.ttf")
+	require.NoError(t, os.WriteFile(notAFont, []byte("not a font"), 0644))
+
+	for _, config := range []Config{
+		{FontFile: filepath.Join(dir, "missing.ttf")},
+		{FontFile: notAFont},
+		{FallbackFonts: []string{notAFont}},
+	} {
+		config.Width, config.Height = 4, 1
+		rs := NewRenderingStage(config)
+		assert.Equal(t, bitmapCellWidth, rs.charWidth, "the stage falls back to the bitmap font")
+
+		frame := filepath.Join(dir, "frame.png")
+		assert.Error(t, rs.CaptureFrame(frame))
+		assert.NoFileExists(t, frame)
+	}
+
+	// A good font still captures
+	rs := NewRenderingStage(Config{Width: 4, Height: 1, FontSize: 12, Scale: 2})
+	frame := filepath.Join(dir, "frame.png")
+	require.NoError(t, rs.CaptureFrame(frame))
+	file, err := os.Open(frame)
+	require.NoError(t, err)
+	defer file.Close()
+	config, err := png.DecodeConfig(file)
+	require.NoError(t, err)
+	assert.Equal(t, 4*14, config.Width)
+}
@@ -0,0 +1,235 @@
+package steadicam
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// goldenEnv regenerates golden files instead of comparing against them:
+// run `STEADICAM_UPDATE_GOLDEN=1 go test ./...`. An environment variable keeps
+// the library from registering flags in every test binary that imports it.
+const goldenEnv = "STEADICAM_UPDATE_GOLDEN"
+
+// goldenDir is where golden files live, relative to the package under test
+var goldenDir = "testdata"
+
+// shouldUpdateGolden reports whether golden files should be rewritten
+func shouldUpdateGolden() bool {
+	switch strings.ToLower(os.Getenv(goldenEnv)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// AssertViewMatchesGolden compares the ANSI-stripped view with testdata/<name>.golden.
+//
+// On mismatch the trip context carries a unified diff of golden versus actual.
+// Run the tests with STEADICAM_UPDATE_GOLDEN=1 to write
+// the current view as the new golden file. Names may contain slashes to group
+// goldens in subdirectories.
+//
+// Example:
+//
+//	director.Type("help").PressEnter().AssertViewMatchesGolden("help_screen")
+func (d *StageDirector) AssertViewMatchesGolden(name string) *StageDirector {
+	view := normalizeGolden(stripANSI(d.getCurrentView()))
+	return d.assertGolden(name, name+".golden", view)
+}
+
+// AssertStyledViewMatchesGolden compares the raw view, escape codes included,
+// with testdata/<name>.styled.golden. Use it to pin down colors and attributes
+// as well as layout; the diff shows escape characters as \x1b.
+func (d *StageDirector) AssertStyledViewMatchesGolden(name string) *StageDirector {
+	view := normalizeGolden(d.getCurrentView())
+	return d.assertGolden(name, name+".styled.golden", view)
+}
+
+// assertGolden compares content against a golden file, or rewrites it in update mode
+func (d *StageDirector) assertGolden(name, file, actual string) *StageDirector {
+	path := filepath.Join(goldenDir, filepath.FromSlash(file))
+
+	if shouldUpdateGolden() {
+		if err := writeGolden(path, actual); err != nil {
+			d.recordTrip(newStageTrip("GOLDEN_WRITE_FAILED", fmt.Sprintf("Could not update golden file %s: %v", path, err), map[string]interface{}{
+				"golden": path,
+				"error":  err.Error(),
+			}))
+			return d
+		}
+		if d.t != nil {
+			d.t.Logf("📝 Updated golden file %s", path)
+		}
+		d.recordStageAction("assertion", "golden_updated="+name)
+		return d
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		d.recordTrip(newStageTrip("assertion", fmt.Sprintf("Golden file %s does not exist; run with %s=1 to create it", path, goldenEnv), map[string]interface{}{
+			"golden":      path,
+			"actual_view": actual,
+		}))
+		return d
+	}
+	if err != nil {
+		d.recordTrip(newStageTrip("GOLDEN_READ_FAILED", fmt.Sprintf("Could not read golden file %s: %v", path, err), map[string]interface{}{
+			"golden": path,
+			"error":  err.Error(),
+		}))
+		return d
+	}
+
+	expected := normalizeGolden(string(data))
+	if expected != actual {
+		diff := unifiedDiff(path, "actual", visibleEscapes(expected), visibleEscapes(actual))
+		d.recordTrip(newStageTrip("assertion", fmt.Sprintf("View does not match golden file %s", path), map[string]interface{}{
+			"golden": path,
+			"diff":   diff,
+		}))
+		return d
+	}
+
+	d.recordStageAction("assertion", "golden="+name)
+	return d
+}
+
+// writeGolden writes a golden file, creating its directory as needed
+func writeGolden(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
+
+// normalizeGolden makes views comparable across platforms and editors:
+// CRLF becomes LF and the file always ends with a single newline
+func normalizeGolden(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.TrimRight(content, "\n") + "\n"
+}
+
+// visibleEscapes spells out ESC so styled diffs are readable in a terminal
+func visibleEscapes(content string) string {
+	return strings.ReplaceAll(content, "\x1b", `\x1b`)
+}
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed, '+' added
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns a unified diff of two texts, or "" if they are equal.
+// Views are small enough that a plain LCS table is plenty fast.
+func unifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+	a, b := diffLines(from), diffLines(to)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+
+	// Emit hunks: runs of changes padded with context, merged when close together
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		first := max(0, start-diffContext)
+		last := start
+		for k := start; k < len(ops) && k <= last+2*diffContext; k++ {
+			if ops[k].kind != ' ' {
+				last = k
+			}
+		}
+		end := min(len(ops), last+diffContext+1)
+
+		// Line numbers of the hunk in each file
+		fromLine, toLine := 1, 1
+		for _, op := range ops[:first] {
+			if op.kind != '+' {
+				fromLine++
+			}
+			if op.kind != '-' {
+				toLine++
+			}
+		}
+		fromCount, toCount := 0, 0
+		for _, op := range ops[first:end] {
+			if op.kind != '+' {
+				fromCount++
+			}
+			if op.kind != '-' {
+				toCount++
+			}
+		}
+
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(fromLine, fromCount), hunkRange(toLine, toCount))
+		for _, op := range ops[first:end] {
+			fmt.Fprintf(&out, "%c%s\n", op.kind, op.line)
+		}
+		start = end
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+// diffLines splits text into lines, treating a final newline as a terminator
+func diffLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// hunkRange formats a unified diff range, using the empty-range convention of diff(1)
+func hunkRange(line, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", line-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, count)
+}
@@ -0,0 +1,93 @@
+package steadicam
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStageDirector_AssertViewMatchesGolden tests comparing, diffing and updating golden views
+func TestStageDirector_AssertViewMatchesGolden(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, statusModel{status: "loading"}, StageConfig{
+		Timeout:      time.Second,
+		TypingSpeed:  0,
+		CaptureViews: false,
+		MaxRetries:   0,
+	})
+	defer director.Stop()
+
+	// The committed golden pins the layout without escape codes
+	director.Start().PressEnter().AssertViewMatchesGolden("status_view")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+
+	// Redirect goldens to a scratch directory for the failure and update paths
+	defer func(dir string) { goldenDir = dir }(goldenDir)
+	goldenDir = t.TempDir()
+
+	director.AssertViewMatchesGolden("missing")
+	require.NotNil(t, director.lastTrip)
+	assert.Contains(t, director.lastTrip.Message, goldenEnv+"=1")
+
+	path := filepath.Join(goldenDir, "nested", "status.styled.golden")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("\x1b[1m•\x1b[0m item a\r\n\x1b[7mbusy\x1b[0m\r\n"), 0644))
+
+	director.failed = false
+	director.AssertStyledViewMatchesGolden("nested/status")
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t,
+		"--- "+path+"\n"+
+			"+++ actual\n"+
+			"@@ -1,2 +1,2 @@\n"+
+			` \x1b[1m•\x1b[0m item a`+"\n"+
+			`-\x1b[7mbusy\x1b[0m`+"\n"+
+			`+\x1b[7mready\x1b[0m`,
+		director.lastTrip.Context["diff"])
+
+	// Update mode rewrites the golden, after which it matches
+	t.Setenv(goldenEnv, "1")
+	director.failed = false
+	director.lastTrip = nil
+	director.AssertStyledViewMatchesGolden("nested/status")
+	assert.Nil(t, director.lastTrip)
+
+	t.Setenv(goldenEnv, "")
+	director.AssertStyledViewMatchesGolden("nested/status")
+	assert.Nil(t, director.lastTrip)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "\x1b[1m•\x1b[0m item a\n\x1b[7mready\x1b[0m\n", string(data))
+}
+
+// TestUnifiedDiff tests hunk grouping and line ranges
+func TestUnifiedDiff(t *testing.T) {
+	assert.Equal(t, "", unifiedDiff("a", "b", "same\n", "same\n"))
+
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	to := "1\nTWO\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	assert.Equal(t,
+		"--- golden\n"+
+			"+++ actual\n"+
+			"@@ -1,5 +1,5 @@\n"+
+			" 1\n"+
+			"-2\n"+
+			"+TWO\n"+
+			" 3\n"+
+			" 4\n"+
+			" 5\n"+
+			"@@ -10,3 +10,4 @@\n"+
+			" 10\n"+
+			" 11\n"+
+			" 12\n"+
+			"+13",
+		unifiedDiff("golden", "actual", from, to))
+
+	assert.Equal(t,
+		"--- golden\n+++ actual\n@@ -0,0 +1 @@\n+new",
+		unifiedDiff("golden", "actual", "", "new"))
+}
@@ -0,0 +1,111 @@
+package steadicam
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultSettleWindow is how long a stage must stay quiet to count as idle
+const defaultSettleWindow = 50 * time.Millisecond
+
+// WithSettleWindow sets how long the model must stay quiet before WaitForIdle returns
+func (d *StageDirector) WithSettleWindow(window time.Duration) *StageDirector {
+	d.config.SettleWindow = window
+	return d
+}
+
+// settleWindow returns the configured settle window or the default
+func (d *StageDirector) settleWindow() time.Duration {
+	if d.config.SettleWindow > 0 {
+		return d.config.SettleWindow
+	}
+	return defaultSettleWindow
+}
+
+// WaitForIdle waits until the application has settled: no message has reached
+// the model, no command is still running, and the view has not changed for the
+// settle window (StageConfig.SettleWindow, 50ms by default).
+//
+// Use it after an interaction whose effects arrive through commands, instead of
+// guessing with Wait. A model that never goes quiet - a spinner on tea.Tick, a
+// command that never returns - times out with a WAIT_IDLE_TIMEOUT trip; run such
+// models on a FakeClock so their timers only fire when the test advances time.
+//
+// Example:
+//
+//	director.PressEnter().WaitForIdle().AssertViewContains("3 results")
+func (d *StageDirector) WaitForIdle() *StageDirector {
+	return d.WaitForIdleWithin(d.config.Timeout)
+}
+
+// WaitForIdleWithin is WaitForIdle with its own timeout instead of the stage timeout
+func (d *StageDirector) WaitForIdleWithin(timeout time.Duration) *StageDirector {
+	if d.failed {
+		return d
+	}
+
+	settle := d.settleWindow()
+	start := time.Now()
+
+	updates, unsubscribe := d.subscribe()
+	defer unsubscribe()
+
+	lastView := d.getCurrentView()
+	viewChangedAt := start
+
+	// quietFor reports how long the stage has been quiet, or zero while commands run
+	quietFor := func(now time.Time) time.Duration {
+		if view := d.getCurrentView(); view != lastView {
+			lastView, viewChangedAt = view, now
+		}
+		if d.PendingCommands() > 0 {
+			return 0
+		}
+
+		d.modelMu.RLock()
+		lastMessageAt := d.lastUpdateAt
+		d.modelMu.RUnlock()
+
+		// A finished command's message may still be on its way to the model
+		since := viewChangedAt
+		for _, at := range []time.Time{lastMessageAt, d.commands.lastDone()} {
+			if at.After(since) {
+				since = at
+			}
+		}
+		return now.Sub(since)
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	settled := time.NewTimer(settle)
+	defer settled.Stop()
+
+	for {
+		now := time.Now()
+		quiet := quietFor(now)
+		if quiet >= settle {
+			elapsed := now.Sub(start)
+			d.recordStageResult("wait", fmt.Sprintf("idle=%v", elapsed.Round(time.Millisecond)), elapsed)
+			return d
+		}
+
+		// Check again when the quiet period could be over, or sooner if something happens
+		settled.Reset(settle - quiet)
+		select {
+		case <-updates:
+		case <-settled.C:
+		case <-deadline.C:
+			trip := newStageTrip("WAIT_IDLE_TIMEOUT", fmt.Sprintf("Application did not settle within %v", timeout), map[string]interface{}{
+				"settle_window":    settle.String(),
+				"pending_commands": d.PendingCommands(),
+				"quiet_for":        quietFor(time.Now()).String(),
+				"current_view":     d.getCurrentView(),
+			})
+			d.recordTrip(trip)
+			return d
+		case <-d.ctx.Done():
+			return d
+		}
+	}
+}
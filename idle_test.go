@@ -0,0 +1,63 @@
+package steadicam
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// restlessModel re-arms a wall-clock tick forever, so it never settles
+type restlessModel struct{ ticks int }
+
+func (m restlessModel) Init() tea.Cmd { return m.tick() }
+func (m restlessModel) View() string  { return strings.Repeat(".", m.ticks%10+1) }
+
+func (m restlessModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(tickMsg); ok {
+		m.ticks++
+		return m, m.tick()
+	}
+	return m, nil
+}
+
+func (m restlessModel) tick() tea.Cmd {
+	return tea.Tick(10*time.Millisecond, func(time.Time) tea.Msg { return tickMsg(0) })
+}
+
+// TestStageDirector_WaitForIdle tests waiting until commands finish and the view settles
+func TestStageDirector_WaitForIdle(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, searchModel{status: "idle"}, testAdapterConfig).
+		WithSettleWindow(20 * time.Millisecond)
+	defer director.Stop()
+
+	director.Start().
+		DelayCommands(CommandNamed("fetchResults"), 150*time.Millisecond).
+		PressEnter().
+		WaitForIdle().
+		AssertViewContains("backend: go 1, go 2")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	assert.Equal(t, 0, director.PendingCommands())
+
+	action := director.interactions[len(director.interactions)-2]
+	assert.Equal(t, "wait", action.Type)
+	assert.Contains(t, action.Details, "idle=")
+	assert.GreaterOrEqual(t, action.Result.(time.Duration), 150*time.Millisecond)
+}
+
+// TestStageDirector_WaitForIdleTimeout tests that a model that never settles trips
+func TestStageDirector_WaitForIdleTimeout(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, restlessModel{}, StageConfig{
+		Timeout:      5 * time.Second,
+		SettleWindow: 50 * time.Millisecond,
+	})
+	defer director.Stop()
+
+	director.Start().WaitForIdleWithin(200 * time.Millisecond)
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "WAIT_IDLE_TIMEOUT", director.lastTrip.Type)
+	assert.Equal(t, "50ms", director.lastTrip.Context["settle_window"])
+}
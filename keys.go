@@ -0,0 +1,190 @@
+package steadicam
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keysByName maps BubbleTea key names ("ctrl+c", "shift+tab", "pgdown", "f5")
+// to their key types, built from BubbleTea's own names so the two never drift
+var keysByName = func() map[string]tea.KeyType {
+	names := make(map[string]tea.KeyType)
+	for k := tea.KeyF20; k <= tea.KeyCtrlQuestionMark; k++ {
+		if k == tea.KeyRunes || k == tea.KeySpace {
+			continue
+		}
+		if name := k.String(); name != "" {
+			names[name] = k
+		}
+	}
+
+	// Spellings people reach for that BubbleTea names differently
+	aliases := map[string]tea.KeyType{
+		"space":    tea.KeySpace,
+		"escape":   tea.KeyEscape,
+		"return":   tea.KeyEnter,
+		"del":      tea.KeyDelete,
+		"ins":      tea.KeyInsert,
+		"pageup":   tea.KeyPgUp,
+		"pagedown": tea.KeyPgDown,
+		"ctrl+i":   tea.KeyTab,
+		"ctrl+m":   tea.KeyEnter,
+		"ctrl+[":   tea.KeyEscape,
+		"ctrl+?":   tea.KeyCtrlQuestionMark,
+		"ctrl+`":   tea.KeyCtrlAt,
+	}
+	for name, k := range aliases {
+		names[name] = k
+	}
+	return names
+}()
+
+// ParseKey converts a key spec such as "ctrl+c", "alt+enter", "shift+tab",
+// "pgdown", "f5" or "x" into the KeyMsg a terminal would produce.
+//
+// Modifiers are joined with "+" and matched case-insensitively. "alt+" may be
+// combined with any key and sets KeyMsg.Alt; "ctrl+" and "shift+" select the
+// key types BubbleTea defines for them. A single character is sent as a rune,
+// so "A" and "shift+a" both type an uppercase A. Use "space" for the space bar
+// and "+" for a literal plus sign.
+func ParseKey(spec string) (tea.KeyMsg, error) {
+	s := strings.TrimSpace(spec)
+	if s == "" {
+		return tea.KeyMsg{}, fmt.Errorf("empty key")
+	}
+
+	// The key is everything after the last separating "+", which lets "+" itself be a key
+	key, prefix := s, ""
+	if i := strings.LastIndex(s[:len(s)-1], "+"); i >= 0 {
+		key, prefix = s[i+1:], s[:i]
+	}
+
+	alt, ctrl, shift := false, false, false
+	if prefix != "" {
+		for _, mod := range strings.Split(strings.ToLower(prefix), "+") {
+			switch mod {
+			case "alt", "meta", "option":
+				alt = true
+			case "ctrl", "control":
+				ctrl = true
+			case "shift":
+				shift = true
+			default:
+				return tea.KeyMsg{}, fmt.Errorf("unknown modifier %q in key %q", mod, spec)
+			}
+		}
+	}
+
+	// Single characters are runes; shift only changes their case
+	if utf8.RuneCountInString(key) == 1 && !ctrl {
+		r, _ := utf8.DecodeRuneInString(key)
+		if shift {
+			if !unicode.IsLetter(r) {
+				return tea.KeyMsg{}, fmt.Errorf("cannot shift %q in key %q; send the shifted character instead", key, spec)
+			}
+			r = unicode.ToUpper(r)
+		}
+		if r == ' ' {
+			return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}, Alt: alt}, nil
+		}
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: alt}, nil
+	}
+
+	name := strings.ToLower(key)
+	if shift {
+		name = "shift+" + name
+	}
+	if ctrl {
+		name = "ctrl+" + name
+	}
+	keyType, ok := keysByName[name]
+	if !ok {
+		return tea.KeyMsg{}, fmt.Errorf("unknown key %q", spec)
+	}
+
+	msg := tea.KeyMsg{Type: keyType, Alt: alt}
+	if keyType == tea.KeySpace {
+		msg.Runes = []rune{' '}
+	}
+	return msg, nil
+}
+
+// keyName is the canonical name recorded for a keypress action
+func keyName(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace {
+		if msg.Alt {
+			return "alt+space"
+		}
+		return "space"
+	}
+	return msg.String()
+}
+
+// Press simulates pressing a single key or key combination.
+//
+// The spec uses BubbleTea's key names with modifiers joined by "+", so the
+// strings your Update matches on are the strings you press (see ParseKey).
+// An unrecognized spec records an INVALID_KEY trip and sends nothing.
+//
+// Example:
+//
+//	director.Press("ctrl+c")
+//	director.Press("alt+enter").Press("shift+tab").Press("pgdown")
+func (d *StageDirector) Press(key string) *StageDirector {
+	msg, ok := d.parseKey(key)
+	if !ok {
+		return d
+	}
+	d.sendMessage(msg)
+	d.recordStageAction("keypress", keyName(msg))
+	return d
+}
+
+// PressSequence presses whitespace-separated keys in order, for chords such
+// as Emacs-style "ctrl+x ctrl+s". Every key is parsed before any is sent, so
+// a typo cannot leave the application halfway through a chord.
+//
+// Example:
+//
+//	director.PressSequence("ctrl+x ctrl+s")
+//	director.PressSequence("g g shift+g")
+func (d *StageDirector) PressSequence(sequence string) *StageDirector {
+	var msgs []tea.KeyMsg
+	for _, key := range strings.Fields(sequence) {
+		msg, ok := d.parseKey(key)
+		if !ok {
+			return d
+		}
+		msgs = append(msgs, msg)
+	}
+
+	d.updateMu.Lock()
+	defer d.updateMu.Unlock()
+
+	for i, msg := range msgs {
+		if i > 0 && d.config.TypingSpeed > 0 {
+			time.Sleep(d.config.TypingSpeed)
+		}
+		d.sendMessage(msg)
+		d.recordStageAction("keypress", keyName(msg))
+	}
+	return d
+}
+
+// parseKey parses a key spec, recording a trip if it is invalid
+func (d *StageDirector) parseKey(key string) (tea.KeyMsg, bool) {
+	msg, err := ParseKey(key)
+	if err != nil {
+		trip := newStageTrip("INVALID_KEY", fmt.Sprintf("Invalid key '%s': %v", key, err), map[string]interface{}{
+			"key": key,
+		})
+		d.recordTrip(trip)
+		return tea.KeyMsg{}, false
+	}
+	return msg, true
+}
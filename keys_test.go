@@ -0,0 +1,87 @@
+package steadicam
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyLogModel shows every key it receives, as BubbleTea names it
+type keyLogModel struct {
+	keys []string
+}
+
+func (m keyLogModel) Init() tea.Cmd { return nil }
+func (m keyLogModel) View() string  { return "keys: " + strings.Join(m.keys, ",") }
+
+func (m keyLogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		m.keys = append(m.keys, key.String())
+	}
+	return m, nil
+}
+
+// TestParseKey tests mapping key specs onto BubbleTea key messages
+func TestParseKey(t *testing.T) {
+	cases := map[string]tea.KeyMsg{
+		"ctrl+c":        {Type: tea.KeyCtrlC},
+		"Ctrl+X":        {Type: tea.KeyCtrlX},
+		"alt+enter":     {Type: tea.KeyEnter, Alt: true},
+		"shift+tab":     {Type: tea.KeyShiftTab},
+		"pgdown":        {Type: tea.KeyPgDown},
+		"f5":            {Type: tea.KeyF5},
+		"home":          {Type: tea.KeyHome},
+		"left":          {Type: tea.KeyLeft},
+		"delete":        {Type: tea.KeyDelete},
+		"escape":        {Type: tea.KeyEscape},
+		"ctrl+shift+up": {Type: tea.KeyCtrlShiftUp},
+		"space":         {Type: tea.KeySpace, Runes: []rune{' '}},
+		"x":             {Type: tea.KeyRunes, Runes: []rune{'x'}},
+		"A":             {Type: tea.KeyRunes, Runes: []rune{'A'}},
+		"shift+a":       {Type: tea.KeyRunes, Runes: []rune{'A'}},
+		"alt+b":         {Type: tea.KeyRunes, Runes: []rune{'b'}, Alt: true},
+		"+":             {Type: tea.KeyRunes, Runes: []rune{'+'}},
+		"alt++":         {Type: tea.KeyRunes, Runes: []rune{'+'}, Alt: true},
+	}
+	for spec, expected := range cases {
+		msg, err := ParseKey(spec)
+		if assert.NoError(t, err, spec) {
+			assert.Equal(t, expected, msg, spec)
+		}
+	}
+
+	for _, spec := range []string{"", "hyper+a", "ctrl+nope", "shift+1", "f99"} {
+		_, err := ParseKey(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// TestStageDirector_Press tests pressing keys and chords through the director
+func TestStageDirector_Press(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, keyLogModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().
+		Press("alt+enter").
+		PressSequence("ctrl+x ctrl+s").
+		Press("space").
+		WaitForText("keys: alt+enter,ctrl+x,ctrl+s, ")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+
+	var pressed []string
+	for _, action := range director.interactions {
+		if action.Type == "keypress" {
+			pressed = append(pressed, action.Details.(string))
+		}
+	}
+	assert.Equal(t, []string{"alt+enter", "ctrl+x", "ctrl+s", "space"}, pressed)
+
+	// A bad key anywhere in a sequence sends none of it
+	director.PressSequence("ctrl+x ctrl+nope")
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "INVALID_KEY", director.lastTrip.Type)
+	assert.Len(t, director.Model().(keyLogModel).keys, 4)
+}
@@ -0,0 +1,358 @@
+package steadicam
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sbvh/qntx/cmd/repl/bubble/steadicam/trip"
+)
+
+// Region is a rectangle of terminal cells addressed from the top-left corner.
+// Rows and columns are zero-based; a Width or Height of zero or less extends
+// the region to the right or bottom edge of the screen.
+type Region struct {
+	Row    int
+	Col    int
+	Width  int
+	Height int
+}
+
+// Row returns a region covering a single full screen row
+func Row(row int) Region {
+	return Region{Row: row, Height: 1}
+}
+
+// String describes the region for trip reports
+func (r Region) String() string {
+	return fmt.Sprintf("rows %d+%d, cols %d+%d", r.Row, r.Height, r.Col, r.Width)
+}
+
+// RegionText returns the plain text inside a rectangle of the screen, one line
+// per row with trailing blanks trimmed
+func (s *Screen) RegionText(r Region) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	top, left := clampInt(r.Row, 0, s.height), clampInt(r.Col, 0, s.width)
+	bottom, right := s.height, s.width
+	if r.Height > 0 {
+		bottom = clampInt(top+r.Height, top, s.height)
+	}
+	if r.Width > 0 {
+		right = clampInt(left+r.Width, left, s.width)
+	}
+
+	lines := make([]string, 0, bottom-top)
+	for _, row := range s.cells()[top:bottom] {
+		lines = append(lines, rowText(row[left:right]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// AssertViewMatches verifies that the ANSI-stripped view matches a regular expression
+func (d *StageDirector) AssertViewMatches(pattern string) *StageDirector {
+	re, ok := d.compilePattern(pattern)
+	if !ok {
+		return d
+	}
+
+	view := stripANSI(d.getCurrentView())
+	if !re.MatchString(view) {
+		trip := newStageTrip("assertion", "View does not match pattern: "+pattern, map[string]interface{}{
+			"pattern": pattern,
+			"excerpt": excerpt(view, nil),
+		})
+		d.recordTrip(trip)
+		return d
+	}
+	d.recordStageAction("assertion", "matches="+pattern)
+	return d
+}
+
+// WaitForMatch waits until the ANSI-stripped view matches a regular expression
+func (d *StageDirector) WaitForMatch(pattern string) *StageDirector {
+	if d.failed {
+		return d
+	}
+	re, ok := d.compilePattern(pattern)
+	if !ok {
+		return d
+	}
+
+	var view string
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		view = stripANSI(d.getCurrentView())
+		return re.MatchString(view)
+	})
+	if err == errWaitTimeout {
+		trip := newStageTrip("WAIT_MATCH_TIMEOUT", fmt.Sprintf("Timeout waiting for pattern '%s'", pattern), map[string]interface{}{
+			"pattern": pattern,
+			"excerpt": excerpt(view, nil),
+		})
+		d.recordTrip(trip)
+	}
+	return d
+}
+
+// AssertPlainViewContains verifies that the view contains text once escape codes are stripped.
+// Unlike AssertViewContains, styled text such as "\x1b[1mfoo\x1b[0m bar" matches "foo bar".
+func (d *StageDirector) AssertPlainViewContains(text string) *StageDirector {
+	view := stripANSI(d.getCurrentView())
+	if !strings.Contains(view, text) {
+		d.recordTrip(missingTextTrip("View does not contain expected text: "+text, text, view))
+		return d
+	}
+	d.recordStageAction("assertion", "plain_contains="+text)
+	return d
+}
+
+// WaitForPlainText waits for text to appear in the view once escape codes are stripped
+func (d *StageDirector) WaitForPlainText(text string) *StageDirector {
+	if d.failed {
+		return d
+	}
+
+	var view string
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		view = stripANSI(d.getCurrentView())
+		return strings.Contains(view, text)
+	})
+	if err == errWaitTimeout {
+		trip := missingTextTrip(fmt.Sprintf("Timeout waiting for text '%s'", text), text, view)
+		trip.Type = "WAIT_TEXT_TIMEOUT"
+		d.recordTrip(trip)
+	}
+	return d
+}
+
+// AssertViewNotContains verifies that text is absent from the ANSI-stripped view
+func (d *StageDirector) AssertViewNotContains(text string) *StageDirector {
+	view := stripANSI(d.getCurrentView())
+	if spans := findAll(view, text); len(spans) > 0 {
+		trip := newStageTrip("assertion", "View contains unexpected text: "+text, map[string]interface{}{
+			"unexpected": text,
+			"excerpt":    excerpt(view, spans),
+		})
+		d.recordTrip(trip)
+		return d
+	}
+	d.recordStageAction("assertion", "not_contains="+text)
+	return d
+}
+
+// WaitForTextGone waits until text no longer appears in the ANSI-stripped view
+func (d *StageDirector) WaitForTextGone(text string) *StageDirector {
+	if d.failed {
+		return d
+	}
+
+	var view string
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		view = stripANSI(d.getCurrentView())
+		return !strings.Contains(view, text)
+	})
+	if err == errWaitTimeout {
+		trip := newStageTrip("WAIT_TEXT_GONE_TIMEOUT", fmt.Sprintf("Timeout waiting for text '%s' to disappear", text), map[string]interface{}{
+			"unexpected": text,
+			"excerpt":    excerpt(view, findAll(view, text)),
+		})
+		d.recordTrip(trip)
+	}
+	return d
+}
+
+// AssertTextCount verifies how many times text occurs in the ANSI-stripped view
+func (d *StageDirector) AssertTextCount(text string, expected int) *StageDirector {
+	view := stripANSI(d.getCurrentView())
+	spans := findAll(view, text)
+	if len(spans) != expected {
+		trip := newStageTrip("assertion", fmt.Sprintf("Expected %d occurrences of '%s', found %d", expected, text, len(spans)), map[string]interface{}{
+			"text":     text,
+			"expected": expected,
+			"actual":   len(spans),
+			"excerpt":  excerpt(view, spans),
+		})
+		d.recordTrip(trip)
+		return d
+	}
+	d.recordStageAction("assertion", fmt.Sprintf("count=%d:%s", expected, text))
+	return d
+}
+
+// AssertRegionContains verifies that a rectangle of the virtual terminal shows text.
+// Matching within a region distinguishes, for example, a status bar from the
+// same words appearing in the main content.
+func (d *StageDirector) AssertRegionContains(region Region, text string) *StageDirector {
+	content := d.Screen().RegionText(region)
+	if !strings.Contains(content, text) {
+		trip := missingTextTrip(fmt.Sprintf("Region (%s) does not contain expected text: %s", region, text), text, content)
+		trip.Context["region"] = region.String()
+		d.recordTrip(trip)
+		return d
+	}
+	d.recordStageAction("assertion", fmt.Sprintf("region_contains=%s@%s", text, region))
+	return d
+}
+
+// AssertRegionMatches verifies that a rectangle of the virtual terminal matches a regular expression
+func (d *StageDirector) AssertRegionMatches(region Region, pattern string) *StageDirector {
+	re, ok := d.compilePattern(pattern)
+	if !ok {
+		return d
+	}
+
+	content := d.Screen().RegionText(region)
+	if !re.MatchString(content) {
+		trip := newStageTrip("assertion", fmt.Sprintf("Region (%s) does not match pattern: %s", region, pattern), map[string]interface{}{
+			"pattern": pattern,
+			"region":  region.String(),
+			"excerpt": excerpt(content, nil),
+		})
+		d.recordTrip(trip)
+		return d
+	}
+	d.recordStageAction("assertion", fmt.Sprintf("region_matches=%s@%s", pattern, region))
+	return d
+}
+
+// compilePattern compiles a regular expression, recording a trip if it is invalid
+func (d *StageDirector) compilePattern(pattern string) (*regexp.Regexp, bool) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		trip := newStageTrip("INVALID_PATTERN", fmt.Sprintf("Invalid pattern '%s': %v", pattern, err), map[string]interface{}{
+			"pattern": pattern,
+		})
+		d.recordTrip(trip)
+		return nil, false
+	}
+	return re, true
+}
+
+// missingTextTrip builds an assertion trip that points at the closest partial match
+func missingTextTrip(message, expected, content string) *trip.Trip {
+	var spans []span
+	context := map[string]interface{}{"expected": expected}
+	if closest, ok := closestMatch(content, expected); ok {
+		spans = []span{closest}
+		context["closest_match"] = content[closest.start:closest.end]
+	}
+	context["excerpt"] = excerpt(content, spans)
+	return newStageTrip("assertion", message, context)
+}
+
+// span marks a byte range of matched text
+type span struct {
+	start int
+	end   int
+}
+
+// findAll returns every non-overlapping occurrence of text
+func findAll(content, text string) []span {
+	if text == "" {
+		return nil
+	}
+
+	var spans []span
+	for offset := 0; ; {
+		i := strings.Index(content[offset:], text)
+		if i < 0 {
+			return spans
+		}
+		start := offset + i
+		spans = append(spans, span{start, start + len(text)})
+		offset = start + len(text)
+	}
+}
+
+// minClosestMatch is the shortest prefix worth pointing at as a near miss
+const minClosestMatch = 3
+
+// closestMatch finds the longest prefix of text that does occur in content,
+// which usually lands on the spot where the expected text went wrong
+func closestMatch(content, text string) (span, bool) {
+	for n := len(text) - 1; n >= minClosestMatch; n-- {
+		if !utf8.RuneStart(text[n]) {
+			continue
+		}
+		if i := strings.Index(content, text[:n]); i >= 0 {
+			return span{i, i + n}, true
+		}
+	}
+	return span{}, false
+}
+
+// maxExcerptLines bounds how much of the view a trip report quotes
+const maxExcerptLines = 12
+
+// excerpt renders numbered lines of content with carets under each span.
+// Lines around the spans are quoted; without spans the top of the content is.
+func excerpt(content string, spans []span) string {
+	lines := strings.Split(content, "\n")
+
+	// Map each span onto its line as a (column, width) marker in display columns
+	type marker struct{ col, width int }
+	markers := make(map[int][]marker)
+	lineStart := 0
+	for i, line := range lines {
+		lineEnd := lineStart + len(line)
+		for _, sp := range spans {
+			if sp.start < lineStart || sp.start > lineEnd {
+				continue
+			}
+			end := min(sp.end, lineEnd)
+			markers[i] = append(markers[i], marker{
+				col:   displayWidth(content[lineStart:sp.start]),
+				width: max(1, displayWidth(content[sp.start:end])),
+			})
+		}
+		lineStart = lineEnd + 1
+	}
+
+	// Choose the lines to quote: one line of context around each marked line
+	show := make([]bool, len(lines))
+	if len(markers) == 0 {
+		for i := 0; i < len(lines) && i < maxExcerptLines; i++ {
+			show[i] = true
+		}
+	} else {
+		for i := range markers {
+			for j := max(0, i-1); j <= min(len(lines)-1, i+1); j++ {
+				show[j] = true
+			}
+		}
+	}
+
+	var out strings.Builder
+	shown, skipped := 0, false
+	for i, line := range lines {
+		if !show[i] || shown >= maxExcerptLines {
+			skipped = true
+			continue
+		}
+		if skipped && shown > 0 {
+			out.WriteString("     ...\n")
+		}
+		skipped = false
+		shown++
+
+		fmt.Fprintf(&out, "%4d | %s\n", i+1, line)
+		if ms := markers[i]; len(ms) > 0 {
+			underline := []rune(strings.Repeat(" ", displayWidth(line)))
+			for _, m := range ms {
+				for c := m.col; c < m.col+m.width; c++ {
+					if c >= len(underline) {
+						underline = append(underline, ' ')
+					}
+					underline[c] = '^'
+				}
+			}
+			fmt.Fprintf(&out, "     | %s\n", strings.TrimRight(string(underline), " "))
+		}
+	}
+	if skipped {
+		out.WriteString("     ...\n")
+	}
+	return strings.TrimRight(out.String(), "\n")
+}
@@ -0,0 +1,120 @@
+package steadicam
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusModel renders styled content above a status bar
+type statusModel struct {
+	items  []string
+	status string
+}
+
+func (m statusModel) Init() tea.Cmd { return nil }
+
+func (m statusModel) View() string {
+	var view strings.Builder
+	for _, item := range m.items {
+		view.WriteString("\x1b[1m•\x1b[0m " + item + "\n")
+	}
+	view.WriteString("\x1b[7m" + m.status + "\x1b[0m")
+	return view.String()
+}
+
+func (m statusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.Type {
+		case tea.KeyEnter:
+			m.items = append(m.items, "item "+string(rune('a'+len(m.items))))
+			m.status = "ready"
+		case tea.KeyBackspace:
+			m.items = nil
+		}
+	}
+	return m, nil
+}
+
+// TestStageDirector_Matchers tests regex, plain-text, count and absence matchers
+func TestStageDirector_Matchers(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, statusModel{status: "loading"}, StageConfig{
+		Timeout:      time.Second,
+		TypingSpeed:  0,
+		CaptureViews: false,
+		MaxRetries:   0,
+	})
+	defer director.Stop()
+
+	director.Start().
+		PressEnter().
+		PressEnter().
+		WaitForMatch(`item [a-z]\n`).
+		AssertViewMatches(`(?m)^• item b$`).
+		AssertPlainViewContains("• item a").
+		AssertTextCount("item", 2).
+		AssertViewNotContains("loading").
+		AssertRegionContains(Row(2), "ready").
+		AssertRegionMatches(Region{Row: 0, Col: 2, Height: 2}, `^item a\nitem b$`)
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+
+	// Text is absent from a region even when it appears elsewhere on screen
+	director.AssertRegionContains(Region{Row: 0, Height: 2}, "ready")
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "rows 0+2, cols 0+0", director.lastTrip.Context["region"])
+
+	director.failed = false
+	director.PressBackspace().WaitForTextGone("item")
+	assert.False(t, director.HasFailed(), director.getErrorMessage())
+
+	director.AssertViewMatches(`(unclosed`)
+	assert.Equal(t, "INVALID_PATTERN", director.lastTrip.Type)
+}
+
+// TestMatcherTrips_Excerpts tests that failed matchers point at the relevant text
+func TestMatcherTrips_Excerpts(t *testing.T) {
+	view := "header\nfirst line\nResults: 3 found\nfooter"
+
+	// Missing text points at the longest prefix that does occur
+	trip := missingTextTrip("missing", "Results: 4 found", view)
+	assert.Equal(t, "Results: ", trip.Context["closest_match"])
+	assert.Equal(t,
+		"   2 | first line\n"+
+			"   3 | Results: 3 found\n"+
+			"     | ^^^^^^^^^\n"+
+			"   4 | footer",
+		trip.Context["excerpt"])
+
+	// Every occurrence is underlined
+	assert.Equal(t,
+		"   1 | a-b-a\n"+
+			"     | ^   ^",
+		excerpt("a-b-a", findAll("a-b-a", "a")))
+
+	// Carets line up under text after wide characters
+	assert.Equal(t,
+		"   1 | 漢字 ab\n"+
+			"     |      ^^",
+		excerpt("漢字 ab", findAll("漢字 ab", "ab")))
+
+	// Without spans the top of the view is quoted, bounded in length
+	long := strings.Repeat("line\n", 20)
+	lines := strings.Split(excerpt(long, nil), "\n")
+	assert.Len(t, lines, maxExcerptLines+1)
+	assert.Equal(t, "     ...", lines[len(lines)-1])
+}
+
+// TestScreen_RegionText tests extracting a rectangle of cells
+func TestScreen_RegionText(t *testing.T) {
+	screen := NewScreen(10, 4)
+	screen.WriteString("\x1b[20hab cd\nef gh\nij kl")
+
+	assert.Equal(t, "ef gh", screen.RegionText(Row(1)))
+	assert.Equal(t, "cd\ngh", screen.RegionText(Region{Row: 0, Col: 3, Width: 2, Height: 2}))
+	assert.Equal(t, "kl\n", screen.RegionText(Region{Row: 2, Col: 3}))
+	assert.Equal(t, "", screen.RegionText(Region{Row: 9}))
+}
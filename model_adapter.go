@@ -0,0 +1,185 @@
+package steadicam
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// InputReporter is implemented by models that can report their current input text.
+// Plain tea.Model values passed to NewStageDirectorForModel are checked for it at runtime.
+type InputReporter interface {
+	CurrentInput() string
+}
+
+// ModeReporter is implemented by models that can report their current mode
+type ModeReporter interface {
+	CurrentMode() string
+}
+
+// ConditionChecker is implemented by models that support custom wait conditions
+type ConditionChecker interface {
+	CheckCondition(condition string) bool
+}
+
+// ModelOption configures how a plain tea.Model is inspected by the director
+type ModelOption func(*modelInspectors)
+
+// modelInspectors holds the user-supplied functions that read state out of a plain tea.Model
+type modelInspectors struct {
+	input     func(tea.Model) string
+	mode      func(tea.Model) string
+	condition func(tea.Model, string) bool
+}
+
+// WithInputInspector reads the current input from the model, e.g. from a textinput.Model.
+// It takes precedence over an InputReporter implementation.
+//
+// Example:
+//
+//	steadicam.WithInputInspector(func(m tea.Model) string {
+//		return m.(textinput.Model).Value()
+//	})
+func WithInputInspector(inspect func(tea.Model) string) ModelOption {
+	return func(i *modelInspectors) {
+		i.input = inspect
+	}
+}
+
+// WithModeInspector reads the current mode from the model.
+// It takes precedence over a ModeReporter implementation.
+func WithModeInspector(inspect func(tea.Model) string) ModelOption {
+	return func(i *modelInspectors) {
+		i.mode = inspect
+	}
+}
+
+// WithConditionInspector evaluates named conditions against the model.
+// It takes precedence over a ConditionChecker implementation.
+func WithConditionInspector(check func(tea.Model, string) bool) ModelOption {
+	return func(i *modelInspectors) {
+		i.condition = check
+	}
+}
+
+// modelCapabilities is implemented by models that may not report every REPL detail.
+// Models that implement REPLModel directly are assumed to report everything.
+type modelCapabilities interface {
+	reportsInput() bool
+	reportsMode() bool
+	checksConditions() bool
+}
+
+// modelAdapter presents any tea.Model as a REPLModel
+// The stand-in - takes the star's marks so the camera crew can light the scene
+type modelAdapter struct {
+	tea.Model
+	inspectors *modelInspectors
+}
+
+// Update forwards to the wrapped model and keeps the adapter around the result
+func (a modelAdapter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	newModel, cmd := a.Model.Update(msg)
+	if newModel == nil {
+		return nil, cmd
+	}
+	return modelAdapter{Model: newModel, inspectors: a.inspectors}, cmd
+}
+
+// CurrentInput implements REPLModel using the inspector or an InputReporter
+func (a modelAdapter) CurrentInput() string {
+	if a.inspectors.input != nil {
+		return a.inspectors.input(a.Model)
+	}
+	if reporter, ok := a.Model.(InputReporter); ok {
+		return reporter.CurrentInput()
+	}
+	return ""
+}
+
+// CurrentMode implements REPLModel using the inspector or a ModeReporter
+func (a modelAdapter) CurrentMode() string {
+	if a.inspectors.mode != nil {
+		return a.inspectors.mode(a.Model)
+	}
+	if reporter, ok := a.Model.(ModeReporter); ok {
+		return reporter.CurrentMode()
+	}
+	return ""
+}
+
+// CheckCondition implements REPLModel using the inspector or a ConditionChecker
+func (a modelAdapter) CheckCondition(condition string) bool {
+	if a.inspectors.condition != nil {
+		return a.inspectors.condition(a.Model, condition)
+	}
+	if checker, ok := a.Model.(ConditionChecker); ok {
+		return checker.CheckCondition(condition)
+	}
+	return false
+}
+
+func (a modelAdapter) reportsInput() bool {
+	_, ok := a.Model.(InputReporter)
+	return ok || a.inspectors.input != nil
+}
+
+func (a modelAdapter) reportsMode() bool {
+	_, ok := a.Model.(ModeReporter)
+	return ok || a.inspectors.mode != nil
+}
+
+func (a modelAdapter) checksConditions() bool {
+	_, ok := a.Model.(ConditionChecker)
+	return ok || a.inspectors.condition != nil
+}
+
+// NewStageDirectorForModel creates a StageDirector for any BubbleTea model.
+//
+// Unlike NewStageDirector, the model does not need to implement REPLModel.
+// View-based waits, assertions and snapshots work for every model, while
+// mode, input and condition checks are available when the model implements
+// ModeReporter, InputReporter or ConditionChecker, or when an inspector
+// option is supplied. Using them without either records an
+// "UNSUPPORTED_INSPECTION" trip instead of silently comparing empty strings.
+//
+// Example:
+//
+//	director := NewStageDirectorForModel(t, textinput.New(),
+//		steadicam.WithInputInspector(func(m tea.Model) string {
+//			return m.(textinput.Model).Value()
+//		}),
+//	)
+func NewStageDirectorForModel(t testing.TB, model tea.Model, opts ...ModelOption) *StageDirector {
+	return NewStageDirectorForModelWithConfig(t, model, DefaultStageConfig(), opts...)
+}
+
+// NewStageDirectorForModelWithConfig creates a StageDirector for any BubbleTea model with custom configuration
+func NewStageDirectorForModelWithConfig(t testing.TB, model tea.Model, config StageConfig, opts ...ModelOption) *StageDirector {
+	return NewStageDirectorWithConfig(t, adaptModel(model, opts...), config)
+}
+
+// UnwrapModel returns the tea.Model behind a REPLModel handed to a predicate.
+// Models staged through NewStageDirectorForModel are adapted; anything else
+// is returned as is.
+//
+// Example:
+//
+//	director.WaitFor("list loaded", func(m steadicam.REPLModel) bool {
+//		return len(steadicam.UnwrapModel(m).(list.Model).Items()) > 0
+//	})
+func UnwrapModel(model REPLModel) tea.Model {
+	if adapter, ok := model.(modelAdapter); ok {
+		return adapter.Model
+	}
+	return model
+}
+
+// adaptModel wraps a plain tea.Model so the director can stage it
+func adaptModel(model tea.Model, opts ...ModelOption) REPLModel {
+	inspectors := &modelInspectors{}
+	for _, opt := range opts {
+		opt(inspectors)
+	}
+	return modelAdapter{Model: model, inspectors: inspectors}
+}
@@ -0,0 +1,91 @@
+package steadicam
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainCounterModel is a bare tea.Model with no REPL inspection methods
+type plainCounterModel struct {
+	count int
+}
+
+func (m plainCounterModel) Init() tea.Cmd { return nil }
+func (m plainCounterModel) View() string  { return "count: " + string(rune('0'+m.count)) }
+
+func (m plainCounterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok && key.String() == "+" {
+		m.count++
+	}
+	return m, nil
+}
+
+// modeCounterModel adds a ModeReporter to the plain counter
+type modeCounterModel struct {
+	plainCounterModel
+}
+
+func (m modeCounterModel) CurrentMode() string { return "counting" }
+
+func (m modeCounterModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	counter, cmd := m.plainCounterModel.Update(msg)
+	return modeCounterModel{counter.(plainCounterModel)}, cmd
+}
+
+var testAdapterConfig = StageConfig{
+	Timeout:      5 * time.Second,
+	TypingSpeed:  0,
+	CaptureViews: true,
+	MaxRetries:   0,
+}
+
+// TestStageDirectorForModel_PlainModel tests view-based staging of a model without REPL methods
+func TestStageDirectorForModel_PlainModel(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, plainCounterModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().
+		Type("++").
+		WaitForText("count: 2").
+		AssertViewContains("count: 2")
+	assert.False(t, director.HasFailed())
+
+	// The latest model is handed back unwrapped
+	model, ok := director.Model().(plainCounterModel)
+	require.True(t, ok)
+	assert.Equal(t, 2, model.count)
+
+	snapshot := director.GetLatestSnapshot()
+	assert.Equal(t, "count: 2", snapshot.View)
+	assert.Equal(t, "", snapshot.Mode)
+
+	// Mode assertions need a reporter or an inspector
+	director.AssertMode("counting")
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "UNSUPPORTED_INSPECTION", director.lastTrip.Type)
+}
+
+// TestStageDirectorForModel_OptionalInterfaces tests runtime detection and inspector options
+func TestStageDirectorForModel_OptionalInterfaces(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, modeCounterModel{}, testAdapterConfig,
+		WithInputInspector(func(m tea.Model) string {
+			return m.View()
+		}),
+	)
+	defer director.Stop()
+
+	director.Start().
+		Type("+").
+		AssertMode("counting").
+		AssertInputEquals("count: 1")
+	assert.False(t, director.HasFailed())
+
+	// Conditions are neither implemented nor inspected
+	director.AssertNoSearchResults()
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "UNSUPPORTED_INSPECTION", director.lastTrip.Type)
+}
@@ -0,0 +1,272 @@
+package steadicam
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Point is a zero-based cell position on the virtual terminal, X being the column
+type Point struct {
+	X int
+	Y int
+}
+
+// String formats the point as "x,y" for stage actions
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+// ScrollDirection selects which way the mouse wheel turns
+type ScrollDirection int
+
+// Mouse wheel directions
+const (
+	ScrollUp ScrollDirection = iota
+	ScrollDown
+	ScrollLeft
+	ScrollRight
+)
+
+// String returns the direction name used in stage actions
+func (s ScrollDirection) String() string {
+	switch s {
+	case ScrollUp:
+		return "up"
+	case ScrollDown:
+		return "down"
+	case ScrollLeft:
+		return "left"
+	case ScrollRight:
+		return "right"
+	}
+	return fmt.Sprintf("ScrollDirection(%d)", int(s))
+}
+
+// Click simulates a left click (press and release) on the cell at column x, row y.
+//
+// Events carry both the MouseAction/MouseButton fields and the deprecated
+// MouseEventType, exactly as BubbleTea's SGR mouse parser reports them, so
+// models written against either API see a real click. The model does not need
+// to enable mouse tracking for the stage to deliver them.
+//
+// Example:
+//
+//	director.Click(4, 2).AssertViewContains("selected: item 3")
+func (d *StageDirector) Click(x, y int) *StageDirector {
+	return d.click(Point{x, y}, tea.MouseButtonLeft, "click")
+}
+
+// RightClick simulates a right click on the cell at column x, row y
+func (d *StageDirector) RightClick(x, y int) *StageDirector {
+	return d.click(Point{x, y}, tea.MouseButtonRight, "right_click")
+}
+
+// Drag simulates pressing the left button at from, moving through every cell
+// on the straight line to to, and releasing there.
+//
+// Example:
+//
+//	director.Drag(steadicam.Point{X: 10, Y: 0}, steadicam.Point{X: 10, Y: 5})
+func (d *StageDirector) Drag(from, to Point) *StageDirector {
+	msgs := []tea.Msg{mouseMsg(from, tea.MouseButtonLeft, tea.MouseActionPress)}
+	for _, p := range linePoints(from, to)[1:] {
+		msgs = append(msgs, mouseMsg(p, tea.MouseButtonLeft, tea.MouseActionMotion))
+	}
+	msgs = append(msgs, mouseMsg(to, tea.MouseButtonLeft, tea.MouseActionRelease))
+
+	d.sendGesture(msgs)
+	d.pointer = to
+	d.recordStageAction("mouse", fmt.Sprintf("drag=%s->%s", from, to))
+	return d
+}
+
+// Scroll turns the mouse wheel n notches in a direction, under the pointer's
+// last position (the top-left cell until the stage clicks or drags somewhere).
+//
+// Example:
+//
+//	director.Click(0, 5).Scroll(steadicam.ScrollDown, 3)
+func (d *StageDirector) Scroll(direction ScrollDirection, n int) *StageDirector {
+	var button tea.MouseButton
+	switch direction {
+	case ScrollUp:
+		button = tea.MouseButtonWheelUp
+	case ScrollDown:
+		button = tea.MouseButtonWheelDown
+	case ScrollLeft:
+		button = tea.MouseButtonWheelLeft
+	case ScrollRight:
+		button = tea.MouseButtonWheelRight
+	default:
+		d.recordTrip(newStageTrip("INVALID_SCROLL", "Unknown scroll direction: "+direction.String(), map[string]interface{}{
+			"direction": int(direction),
+		}))
+		return d
+	}
+	if n <= 0 {
+		d.recordTrip(newStageTrip("INVALID_SCROLL", fmt.Sprintf("Scroll needs at least one notch, got %d", n), map[string]interface{}{
+			"direction": direction.String(),
+			"notches":   n,
+		}))
+		return d
+	}
+
+	msgs := make([]tea.Msg, 0, n)
+	for i := 0; i < n; i++ {
+		msgs = append(msgs, mouseMsg(d.pointer, button, tea.MouseActionPress))
+	}
+	d.sendGesture(msgs)
+	d.recordStageAction("mouse", fmt.Sprintf("scroll=%s*%d@%s", direction, n, d.pointer))
+	return d
+}
+
+// ClickText finds text on the virtual terminal and clicks the middle of its
+// first occurrence, so tests can select list items and buttons by their label
+// instead of hard-coding coordinates. Missing text records a trip pointing at
+// the closest match on screen.
+//
+// Example:
+//
+//	director.ClickText("[ Save ]").WaitForText("saved")
+func (d *StageDirector) ClickText(text string) *StageDirector {
+	screen := d.Screen()
+	at, ok := screen.Find(text)
+	if !ok {
+		trip := missingTextTrip("Cannot click text that is not on screen: "+text, text, strings.Join(screen.Lines(), "\n"))
+		trip.Type = "CLICK_TARGET_NOT_FOUND"
+		d.recordTrip(trip)
+		return d
+	}
+
+	at.X += (displayWidth(text) - 1) / 2
+	d.click(at, tea.MouseButtonLeft, "click_text="+text)
+	return d
+}
+
+// Find returns the cell where the first occurrence of text starts, scanning
+// rows top to bottom. Columns count display width, so text after wide
+// characters is found where it is drawn. Text spanning several rows is not matched.
+func (s *Screen) Find(text string) (Point, bool) {
+	if text == "" {
+		return Point{}, false
+	}
+	for y, row := range s.Cells() {
+		line := rowText(row)
+		if i := strings.Index(line, text); i >= 0 {
+			return Point{columnAt(row, i), y}, true
+		}
+	}
+	return Point{}, false
+}
+
+// columnAt returns the column of the cell holding byte offset i of rowText(row)
+func columnAt(row []Cell, i int) int {
+	offset := 0
+	for x, cell := range row {
+		offset += len(cell.Grapheme)
+		if offset > i {
+			return x
+		}
+	}
+	return len(row)
+}
+
+// click presses and releases a button on a cell as one gesture
+func (d *StageDirector) click(at Point, button tea.MouseButton, action string) *StageDirector {
+	d.sendGesture([]tea.Msg{
+		mouseMsg(at, button, tea.MouseActionPress),
+		mouseMsg(at, button, tea.MouseActionRelease),
+	})
+	d.pointer = at
+	d.recordStageAction("mouse", fmt.Sprintf("%s@%s", action, at))
+	return d
+}
+
+// sendGesture delivers several messages as a single interaction. Intermediate
+// events such as a button release often leave the view unchanged, so in lossy
+// mode the stage waits once for the whole gesture rather than after each event.
+func (d *StageDirector) sendGesture(msgs []tea.Msg) {
+	if d.program == nil || len(msgs) == 0 {
+		return
+	}
+
+	d.updateMu.Lock()
+	defer d.updateMu.Unlock()
+
+	if d.config.LosslessSync {
+		for _, msg := range msgs {
+			d.sendStagedMessage(msg)
+		}
+	} else {
+		previousView := d.getCurrentView()
+		for _, msg := range msgs {
+			d.program.Send(msg)
+		}
+		d.waitForViewChange(previousView)
+	}
+	d.captureSnapshot("interaction")
+}
+
+// mouseMsg builds a mouse event the way BubbleTea's SGR parser would report it
+func mouseMsg(at Point, button tea.MouseButton, action tea.MouseAction) tea.MouseMsg {
+	msg := tea.MouseMsg{X: at.X, Y: at.Y, Button: button, Action: action}
+
+	switch {
+	case action == tea.MouseActionRelease:
+		msg.Type = tea.MouseRelease
+	case button == tea.MouseButtonLeft:
+		msg.Type = tea.MouseLeft
+	case button == tea.MouseButtonMiddle:
+		msg.Type = tea.MouseMiddle
+	case button == tea.MouseButtonRight:
+		msg.Type = tea.MouseRight
+	case button == tea.MouseButtonWheelUp:
+		msg.Type = tea.MouseWheelUp
+	case button == tea.MouseButtonWheelDown:
+		msg.Type = tea.MouseWheelDown
+	case button == tea.MouseButtonWheelLeft:
+		msg.Type = tea.MouseWheelLeft
+	case button == tea.MouseButtonWheelRight:
+		msg.Type = tea.MouseWheelRight
+	default:
+		msg.Type = tea.MouseMotion
+	}
+	return msg
+}
+
+// linePoints returns the cells on a straight line between two points, both included
+func linePoints(from, to Point) []Point {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	steps := max(abs(dx), abs(dy))
+	if steps == 0 {
+		return []Point{from}
+	}
+
+	points := make([]Point, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		points = append(points, Point{
+			X: from.X + roundDiv(dx*i, steps),
+			Y: from.Y + roundDiv(dy*i, steps),
+		})
+	}
+	return points
+}
+
+// roundDiv divides and rounds half away from zero
+func roundDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b/2) / b)
+	}
+	return (a + b/2) / b
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
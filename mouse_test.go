@@ -0,0 +1,111 @@
+package steadicam
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pickerModel is a list that is selected by clicking and scrolled by the wheel
+type pickerModel struct {
+	items    []string
+	offset   int
+	selected string
+	events   []string
+}
+
+func (m pickerModel) Init() tea.Cmd { return nil }
+
+func (m pickerModel) View() string {
+	var view strings.Builder
+	for _, item := range m.items[m.offset:] {
+		view.WriteString("  " + item + "\n")
+	}
+	fmt.Fprintf(&view, "selected: %s | %s", m.selected, strings.Join(m.events, " "))
+	return view.String()
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	mouse, ok := msg.(tea.MouseMsg)
+	if !ok {
+		return m, nil
+	}
+	m.events = append(m.events, mouse.String())
+
+	switch {
+	case mouse.Button == tea.MouseButtonWheelDown && m.offset < len(m.items)-1:
+		m.offset++
+	case mouse.Button == tea.MouseButtonWheelUp && m.offset > 0:
+		m.offset--
+	case mouse.Type == tea.MouseLeft && mouse.Action == tea.MouseActionPress:
+		if row := m.offset + mouse.Y; row < len(m.items) {
+			m.selected = m.items[row]
+		}
+	}
+	return m, nil
+}
+
+// TestStageDirector_Mouse tests clicks, drags, wheel scrolling and clicking on text
+func TestStageDirector_Mouse(t *testing.T) {
+	model := pickerModel{items: []string{"alpha", "beta", "gamma", "delta"}}
+	director := NewStageDirectorForModelWithConfig(t, model, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().Click(3, 1).WaitForText("selected: beta | left press left release")
+
+	// Wheel events land under the pointer, so clicks then scrolls behave like a real mouse
+	director.Scroll(ScrollDown, 2).WaitForText("  gamma\n  delta\nselected")
+	director.ClickText("delta").WaitForText("selected: delta")
+	assert.Equal(t, Point{4, 1}, director.pointer)
+	director.RightClick(0, 0).WaitForText("right release")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+
+	director.Drag(Point{0, 0}, Point{3, 1}).WaitForText("left motion left motion left motion left release")
+	assert.False(t, director.HasFailed(), director.getErrorMessage())
+
+	var actions []string
+	for _, action := range director.interactions {
+		if action.Type == "mouse" {
+			actions = append(actions, action.Details.(string))
+		}
+	}
+	assert.Equal(t, []string{
+		"click@3,1",
+		"scroll=down*2@3,1",
+		"click_text=delta@4,1",
+		"right_click@0,0",
+		"drag=0,0->3,1",
+	}, actions)
+
+	director.ClickText("epsilon")
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "CLICK_TARGET_NOT_FOUND", director.lastTrip.Type)
+}
+
+// TestStageDirector_ScrollWithoutNotches tests that scrolls of zero or fewer notches trip
+func TestStageDirector_ScrollWithoutNotches(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		model := pickerModel{items: []string{"alpha", "beta"}}
+		director := NewStageDirectorForModelWithConfig(t, model, testAdapterConfig)
+
+		director.Start().Scroll(ScrollDown, n)
+		require.NotNil(t, director.lastTrip, "n=%d", n)
+		assert.Equal(t, "INVALID_SCROLL", director.lastTrip.Type)
+		assert.Equal(t, n, director.lastTrip.Context["notches"])
+		for _, action := range director.interactions {
+			assert.NotEqual(t, "mouse", action.Type, "nothing was scrolled")
+		}
+		director.Stop()
+	}
+}
+
+// TestLinePoints tests the cells visited by a drag
+func TestLinePoints(t *testing.T) {
+	assert.Equal(t, []Point{{0, 0}, {1, 0}, {2, 1}, {3, 1}}, linePoints(Point{0, 0}, Point{3, 1}))
+	assert.Equal(t, []Point{{2, 2}, {2, 1}, {2, 0}}, linePoints(Point{2, 2}, Point{2, 0}))
+	assert.Equal(t, []Point{{5, 5}}, linePoints(Point{5, 5}, Point{5, 5}))
+}
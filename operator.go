@@ -3,9 +3,13 @@ package steadicam
 import (
 	"fmt"
 	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/sbvh/qntx/cmd/repl/bubble/steadicam/trip"
 )
 
@@ -20,20 +24,30 @@ type Operator struct {
 
 // NewOperator creates a test director that can capture smooth visual tracking shots
 // Stanley's trusted camera operator for fluid UI cinematography
-func NewOperator(t *testing.T, model REPLModel, outputDir string) *Operator {
+func NewOperator(t testing.TB, model REPLModel, outputDir string) *Operator {
+	return newOperator(NewStageDirector(t, model), outputDir)
+}
+
+// NewOperatorForModel creates an Operator for any BubbleTea model.
+// See NewStageDirectorForModel for how mode, input and conditions are inspected.
+func NewOperatorForModel(t testing.TB, model tea.Model, outputDir string, opts ...ModelOption) *Operator {
+	return newOperator(NewStageDirectorForModel(t, model, opts...), outputDir)
+}
+
+// newOperator mounts the default rendering stage on a director
+func newOperator(baseDirector *StageDirector, outputDir string) *Operator {
+	width, height := baseDirector.config.terminalSize()
 	config := Config{
-		Width:      80,
-		Height:     24,
+		Width:      width,
+		Height:     height,
 		FontSize:   12,
 		Background: color.RGBA{0, 0, 0, 255},       // Black background
 		Foreground: color.RGBA{255, 255, 255, 255}, // White text
 		OutputDir:  outputDir,
 	}
 
-	baseDirector := NewStageDirector(t, model)
-
 	return &Operator{
-		StageDirector: baseDirector,
+		StageDirector:  baseDirector,
 		renderingStage: NewRenderingStage(config),
 		frameCount:     0,
 		filmDir:        outputDir,
@@ -53,6 +67,26 @@ func (op *Operator) WithTimeout(timeout time.Duration) *Operator {
 	return op
 }
 
+// WithInitialSize sets the terminal size for both the stage and its camera
+func (op *Operator) WithInitialSize(cols, rows int) *Operator {
+	op.StageDirector.WithInitialSize(cols, rows)
+	op.syncRenderingSize()
+	return op
+}
+
+// Resize resizes the terminal and keeps tracking shots at the new size
+func (op *Operator) Resize(cols, rows int) *Operator {
+	op.StageDirector.Resize(cols, rows)
+	op.syncRenderingSize()
+	return op
+}
+
+// syncRenderingSize matches the rendering stage to the director's terminal
+func (op *Operator) syncRenderingSize() {
+	width, height := op.StageDirector.config.terminalSize()
+	op.renderingStage.Resize(width, height)
+}
+
 // Start wraps the base Start method to return *Operator
 func (op *Operator) Start() *Operator {
 	op.StageDirector.Start()
@@ -71,29 +105,171 @@ func (op *Operator) WaitForText(text string) *Operator {
 	return op
 }
 
+// WaitForMatch wraps the base method to return *Operator
+func (op *Operator) WaitForMatch(pattern string) *Operator {
+	op.StageDirector.WaitForMatch(pattern)
+	return op
+}
+
+// WaitForTextGone wraps the base method to return *Operator
+func (op *Operator) WaitForTextGone(text string) *Operator {
+	op.StageDirector.WaitForTextGone(text)
+	return op
+}
+
+// WaitForIdle wraps the base method to return *Operator
+func (op *Operator) WaitForIdle() *Operator {
+	op.StageDirector.WaitForIdle()
+	return op
+}
+
+// WaitForIdleWithin wraps the base method to return *Operator
+func (op *Operator) WaitForIdleWithin(timeout time.Duration) *Operator {
+	op.StageDirector.WaitForIdleWithin(timeout)
+	return op
+}
+
+// Replay wraps the base method to return *Operator
+func (op *Operator) Replay(path string) *Operator {
+	op.StageDirector.Replay(path)
+	op.syncRenderingSize()
+	return op
+}
+
+// ReplayWithTiming wraps the base method to return *Operator
+func (op *Operator) ReplayWithTiming(path string, timing ReplayTiming) *Operator {
+	op.StageDirector.ReplayWithTiming(path, timing)
+	op.syncRenderingSize()
+	return op
+}
+
+// WaitFor wraps the base method to return *Operator
+func (op *Operator) WaitFor(name string, predicate func(REPLModel) bool) *Operator {
+	op.StageDirector.WaitFor(name, predicate)
+	return op
+}
+
+// WaitForWithin wraps the base method to return *Operator
+func (op *Operator) WaitForWithin(name string, timeout time.Duration, predicate func(REPLModel) bool) *Operator {
+	op.StageDirector.WaitForWithin(name, timeout, predicate)
+	return op
+}
+
+// WaitForCondition wraps the base method to return *Operator
+func (op *Operator) WaitForCondition(condition string) *Operator {
+	op.StageDirector.WaitForCondition(condition)
+	return op
+}
+
+// AssertThat wraps the base method to return *Operator
+func (op *Operator) AssertThat(name string, check func(REPLModel) error) *Operator {
+	op.StageDirector.AssertThat(name, check)
+	return op
+}
+
+// Click wraps the base method to return *Operator
+func (op *Operator) Click(x, y int) *Operator {
+	op.StageDirector.Click(x, y)
+	return op
+}
+
+// RightClick wraps the base method to return *Operator
+func (op *Operator) RightClick(x, y int) *Operator {
+	op.StageDirector.RightClick(x, y)
+	return op
+}
+
+// Drag wraps the base method to return *Operator
+func (op *Operator) Drag(from, to Point) *Operator {
+	op.StageDirector.Drag(from, to)
+	return op
+}
+
+// Scroll wraps the base method to return *Operator
+func (op *Operator) Scroll(direction ScrollDirection, n int) *Operator {
+	op.StageDirector.Scroll(direction, n)
+	return op
+}
+
+// ClickText wraps the base method to return *Operator
+func (op *Operator) ClickText(text string) *Operator {
+	op.StageDirector.ClickText(text)
+	return op
+}
+
 // Stop wraps the base method to return stage result
 func (op *Operator) Stop() *StageResult {
 	return op.StageDirector.Stop()
 }
 
+// TrackingShot describes a captured film frame and the snapshot it was rendered from
+type TrackingShot struct {
+	Label    string // Label passed to CaptureTrackingShot
+	Filename string // Path of the PNG frame
+	Snapshot int    // Index into StageResult.Snapshots of the producing snapshot
+}
+
 // CaptureTrackingShot captures the current visual state as a smooth film frame
 // Kubrick's signature fluid camera movement captured digitally
 func (op *Operator) CaptureTrackingShot(label string) *Operator {
-	// Get current view from test director
-	// We'll need to expose getCurrentView() method in the test director
-	// For now, let's use the View() method from the underlying model
-	currentView := "[view capture not yet implemented]"
-
-	// Render to steadicam rig
-	op.renderingStage.RenderText(currentView)
+	snapshot := op.frameScene(label)
 
 	// Generate filename with timestamp and counter for uniqueness
-	timestamp := time.Now().Format("20060102_150405")
+	timestamp := snapshot.Timestamp.Format("20060102_150405")
 	filename := fmt.Sprintf("%s/frame_%s_%03d_%s.png",
 		op.filmDir, timestamp, op.frameCount, label)
 
-	// Save frame
-	if err := op.renderingStage.CaptureFrame(filename); err != nil {
+	return op.saveTrackingShot(snapshot, filename)
+}
+
+// Screenshot captures the current visual state to exactly filename, creating
+// its directory if needed, for frames that docs link to by a stable path.
+// A ".svg" file name produces a crisp, searchable SVG instead of a PNG.
+// The shot is recorded like any other tracking shot, labelled with the file's base name.
+//
+// Example:
+//
+//	op.Type("help").PressEnter().Screenshot("docs/images/help.svg")
+func (op *Operator) Screenshot(filename string) *Operator {
+	snapshot := op.frameScene(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)))
+
+	if dir := filepath.Dir(filename); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			cameraTrip := trip.NewFall("visual", fmt.Sprintf("Cannot create screenshot directory: %v", err),
+				trip.Context{"filename": filename, "original_error": err.Error()})
+			op.StageDirector.recordTrip(cameraTrip)
+			return op
+		}
+	}
+	return op.saveTrackingShot(snapshot, filename)
+}
+
+// frameScene freezes the scene and renders it to the rig, so the frame and
+// its snapshot describe the same moment
+func (op *Operator) frameScene(label string) StageSnapshot {
+	snapshot := op.StageDirector.takeSnapshot()
+	snapshot.Label = label
+
+	// Render to steadicam rig from what the terminal actually shows; before
+	// Start there is no renderer output yet, so fall back to the raw view
+	if op.program != nil {
+		op.renderingStage.RenderScreen(op.StageDirector.Screen())
+	} else {
+		op.renderingStage.RenderText(snapshot.View)
+	}
+	return snapshot
+}
+
+// saveTrackingShot writes the rendered frame and records it against its snapshot
+func (op *Operator) saveTrackingShot(snapshot StageSnapshot, filename string) *Operator {
+	label := snapshot.Label
+
+	// Save frame, as SVG when the file name asks for it
+	capture := op.renderingStage.CaptureFrame
+	if strings.EqualFold(filepath.Ext(filename), ".svg") {
+		capture = op.renderingStage.CaptureSVG
+	}
+	if err := capture(filename); err != nil {
 		// Camera jam is fatal - stop everything
 		cameraTrip := trip.NewFall("visual", fmt.Sprintf("Camera seizure during frame capture: %v", err),
 			trip.Context{"filename": filename, "frame_count": op.frameCount, "original_error": err.Error()})
@@ -102,9 +278,17 @@ func (op *Operator) CaptureTrackingShot(label string) *Operator {
 	}
 
 	op.frameCount++
-	// Record the tracking shot interaction
-	// Note: recordInteraction is not exported, so we'll skip this for now
-	// op.InteractiveTestDirector.recordInteraction("tracking_shot", ...)
+
+	// Tracking shots always keep their snapshot, even with CaptureViews disabled,
+	// so every frame on disk can be traced back to the state that produced it
+	snapshot.Frame = filename
+	op.snapshots = append(op.snapshots, snapshot)
+
+	op.recordStageAction("screenshot", TrackingShot{
+		Label:    label,
+		Filename: filename,
+		Snapshot: len(op.snapshots) - 1,
+	})
 
 	return op
 }
@@ -128,6 +312,12 @@ func (op *Operator) PressTabWithTrackingShot(label string) *Operator {
 	return op
 }
 
+func (op *Operator) PressWithTrackingShot(key string, label string) *Operator {
+	op.Press(key)
+	op.CaptureTrackingShot(label)
+	return op
+}
+
 func (op *Operator) WaitForTextWithTrackingShot(text string, label string) *Operator {
 	op.WaitForText(text)
 	op.CaptureTrackingShot(label)
@@ -0,0 +1,47 @@
+package steadicam
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOperator_CaptureTrackingShot tests that frames are rendered from the real view
+func TestOperator_CaptureTrackingShot(t *testing.T) {
+	model := &mockREPLForInteractions{input: "hello", mode: "tracking_test"}
+	op := NewOperator(t, model, t.TempDir()).WithTimeout(5 * time.Second)
+	op.StageDirector.config.TypingSpeed = 0
+
+	op.Start().CaptureTrackingShot("initial")
+	result := op.Stop()
+
+	require.True(t, result.Success, result.ErrorMessage)
+
+	shots := result.TrackingShots()
+	require.Len(t, shots, 1)
+	assert.Equal(t, "initial", shots[0].Label)
+	assert.Equal(t, "Mock REPL: hello", shots[0].View)
+	assert.Equal(t, "tracking_test", shots[0].Mode)
+	assert.Equal(t, "hello", shots[0].Input)
+
+	_, err := os.Stat(shots[0].Frame)
+	assert.NoError(t, err, "frame should be written to disk")
+
+	// The rendering stage holds the actual view, not a placeholder
+	assert.Equal(t, "Mock REPL: hello", op.renderingStage.Screen().Line(0))
+
+	// The screenshot action links back to the snapshot that produced it
+	var shot *TrackingShot
+	for _, action := range result.Actions {
+		if action.Type == "screenshot" {
+			details := action.Details.(TrackingShot)
+			shot = &details
+		}
+	}
+	require.NotNil(t, shot)
+	assert.Equal(t, shots[0].Frame, shot.Filename)
+	assert.Equal(t, shots[0].Frame, result.Snapshots[shot.Snapshot].Frame)
+}
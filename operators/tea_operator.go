@@ -15,7 +15,7 @@ import (
 // TeaOperator combines teatest's performance with steadicam's visual capture capabilities
 // The best of both worlds: official framework speed + cinematic documentation
 type TeaOperator struct {
-	t              *testing.T
+	t              testing.TB
 	teatestModel   *teatest.TestModel
 	renderingStage *steadicam.RenderingStage
 	frameCount     int
@@ -27,7 +27,7 @@ type TeaOperator struct {
 
 // NewTeaOperator creates a high-performance visual testing operator
 // Combining teatest's speed with Kubrick's visual precision
-func NewTeaOperator(t *testing.T, outputDir string) *TeaOperator {
+func NewTeaOperator(t testing.TB, outputDir string) *TeaOperator {
 	renderConfig := steadicam.Config{
 		Width:      80,
 		Height:     24,
@@ -0,0 +1,295 @@
+package steadicam
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recordingVersion is the file format written by Recorder.Save
+const recordingVersion = 1
+
+// Recorder logs the input a real tea.Program receives - keys, pastes, mouse
+// events, resizes and focus changes - so a session can be replayed as a stage.
+// Messages produced by the model's own commands are not recorded: the replayed
+// model issues those commands again itself.
+//
+// Install it with tea.WithFilter. A program has a single filter, so call
+// Recorder.Filter from your own filter if you already have one.
+//
+// Example:
+//
+//	recorder := steadicam.NewRecorder()
+//	final, err := tea.NewProgram(model, tea.WithFilter(recorder.Filter)).Run()
+//	if err == nil {
+//		err = recorder.Save("testdata/bug-142.recording.json", final)
+//	}
+type Recorder struct {
+	mu     sync.Mutex
+	start  time.Time
+	events []RecordedMsg
+}
+
+// Recording is a recorded session: the input the program received and the
+// view the user was left looking at
+type Recording struct {
+	Version    int           `json:"version"`
+	RecordedAt time.Time     `json:"recorded_at"`
+	FinalView  string        `json:"final_view,omitempty"`
+	Events     []RecordedMsg `json:"events"`
+}
+
+// RecordedMsg is one input message with the time it arrived.
+// Only the fields for its Kind are set.
+type RecordedMsg struct {
+	At   time.Duration `json:"at"`   // Since recording started
+	Kind string        `json:"kind"` // "key", "mouse", "resize", "focus" or "blur"
+
+	Key     string      `json:"key,omitempty"` // Key name, for people reading the file
+	KeyType tea.KeyType `json:"key_type,omitempty"`
+	Runes   string      `json:"runes,omitempty"`
+	Paste   bool        `json:"paste,omitempty"`
+
+	X      int             `json:"x,omitempty"`
+	Y      int             `json:"y,omitempty"`
+	Button tea.MouseButton `json:"button,omitempty"`
+	Action tea.MouseAction `json:"action,omitempty"`
+	Shift  bool            `json:"shift,omitempty"`
+	Ctrl   bool            `json:"ctrl,omitempty"`
+	Alt    bool            `json:"alt,omitempty"` // Alt modifier of keys and mouse events
+
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+}
+
+// NewRecorder creates a recorder; time is measured from this call
+func NewRecorder() *Recorder {
+	return &Recorder{start: time.Now()}
+}
+
+// Filter records input messages and passes every message through unchanged.
+// Its signature matches tea.WithFilter.
+func (r *Recorder) Filter(_ tea.Model, msg tea.Msg) tea.Msg {
+	if event, ok := recordMsg(msg); ok {
+		r.mu.Lock()
+		event.At = time.Since(r.start)
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	}
+	return msg
+}
+
+// Recording returns what has been recorded so far, ending on final's view.
+// A nil final records no view, and replays of it skip the final comparison.
+func (r *Recorder) Recording(final tea.Model) *Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recording := &Recording{
+		Version:    recordingVersion,
+		RecordedAt: r.start,
+		Events:     append([]RecordedMsg(nil), r.events...),
+	}
+	if final != nil {
+		recording.FinalView = final.View()
+	}
+	return recording
+}
+
+// Save writes the recording to path as JSON, typically with the model returned
+// by tea.Program.Run as final
+func (r *Recorder) Save(path string, final tea.Model) error {
+	data, err := json.MarshalIndent(r.Recording(final), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadRecording reads a recording saved by Recorder.Save
+func LoadRecording(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if recording.Version != recordingVersion {
+		return nil, fmt.Errorf("%s: unsupported recording version %d", path, recording.Version)
+	}
+	for i, event := range recording.Events {
+		if event.Msg() == nil {
+			return nil, fmt.Errorf("%s: event %d has unknown kind %q", path, i, event.Kind)
+		}
+	}
+	return &recording, nil
+}
+
+// recordMsg converts an input message into its recorded form
+func recordMsg(msg tea.Msg) (RecordedMsg, bool) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return RecordedMsg{Kind: "key", Key: keyName(msg), KeyType: msg.Type, Runes: string(msg.Runes), Alt: msg.Alt, Paste: msg.Paste}, true
+	case tea.MouseMsg:
+		return RecordedMsg{Kind: "mouse", X: msg.X, Y: msg.Y, Button: msg.Button, Action: msg.Action, Shift: msg.Shift, Ctrl: msg.Ctrl, Alt: msg.Alt}, true
+	case tea.WindowSizeMsg:
+		return RecordedMsg{Kind: "resize", Width: msg.Width, Height: msg.Height}, true
+	case tea.FocusMsg:
+		return RecordedMsg{Kind: "focus"}, true
+	case tea.BlurMsg:
+		return RecordedMsg{Kind: "blur"}, true
+	}
+	return RecordedMsg{}, false
+}
+
+// Msg rebuilds the message that was recorded, or nil for an unknown kind
+func (m RecordedMsg) Msg() tea.Msg {
+	switch m.Kind {
+	case "key":
+		msg := tea.KeyMsg{Type: m.KeyType, Alt: m.Alt, Paste: m.Paste}
+		if m.Runes != "" {
+			msg.Runes = []rune(m.Runes)
+		}
+		return msg
+	case "mouse":
+		// Rebuild the deprecated Type too, for models that still switch on it
+		msg := mouseMsg(Point{X: m.X, Y: m.Y}, m.Button, m.Action)
+		msg.Shift, msg.Ctrl, msg.Alt = m.Shift, m.Ctrl, m.Alt
+		return msg
+	case "resize":
+		return tea.WindowSizeMsg{Width: m.Width, Height: m.Height}
+	case "focus":
+		return tea.FocusMsg{}
+	case "blur":
+		return tea.BlurMsg{}
+	}
+	return nil
+}
+
+// ReplayTiming controls the pauses between replayed messages
+type ReplayTiming int
+
+const (
+	// CompressedTiming sends messages back to back, each processed before the next
+	CompressedTiming ReplayTiming = iota
+	// OriginalTiming keeps the pauses the user made, for bugs that depend on
+	// timers, debouncing or slow commands. With WithClock the pauses advance
+	// the virtual clock instead of sleeping.
+	OriginalTiming
+)
+
+// Replay feeds a recorded session to the model with compressed timing, then
+// verifies that the view ends as it did for the user. Saving a recording of
+// a bug and replaying it turns the reproduction into a regression stage.
+//
+// Example:
+//
+//	director.Start().Replay("testdata/bug-142.recording.json")
+func (d *StageDirector) Replay(path string) *StageDirector {
+	return d.ReplayWithTiming(path, CompressedTiming)
+}
+
+// ReplayWithTiming is Replay with a choice of timing
+func (d *StageDirector) ReplayWithTiming(path string, timing ReplayTiming) *StageDirector {
+	if d.failed {
+		return d
+	}
+
+	recording, err := LoadRecording(path)
+	if err != nil {
+		trip := newStageTrip("REPLAY_LOAD_FAILED", fmt.Sprintf("Could not load recording: %v", err), map[string]interface{}{
+			"recording": path,
+			"error":     err.Error(),
+		})
+		d.recordTrip(trip)
+		return d
+	}
+
+	var last time.Duration
+	for _, event := range recording.Events {
+		if d.failed {
+			return d
+		}
+		if gap := event.At - last; timing == OriginalTiming && gap > 0 {
+			d.pause(gap)
+		}
+		last = event.At
+		d.replayMsg(event.Msg())
+	}
+	d.recordStageAction("replay", fmt.Sprintf("%s events=%d", path, len(recording.Events)))
+
+	if recording.FinalView != "" {
+		d.assertReplayedView(path, recording.FinalView)
+	}
+	return d
+}
+
+// pause lets time pass between replayed messages
+func (d *StageDirector) pause(gap time.Duration) {
+	if d.clock != nil {
+		d.advanceClock(gap)
+		return
+	}
+
+	timer := time.NewTimer(gap)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-d.ctx.Done():
+	}
+}
+
+// replayMsg delivers one recorded message and records it like the matching interaction
+func (d *StageDirector) replayMsg(msg tea.Msg) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		d.Resize(size.Width, size.Height)
+		return
+	}
+
+	d.deliverMessage(msg)
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.Paste {
+			d.recordStageAction("paste", d.truncateString(string(msg.Runes), 50))
+		} else {
+			d.recordStageAction("keypress", keyName(msg))
+		}
+	case tea.MouseMsg:
+		d.pointer = Point{X: msg.X, Y: msg.Y}
+		d.recordStageAction("mouse", fmt.Sprintf("%s@%s", tea.MouseEvent(msg), d.pointer))
+	case tea.FocusMsg:
+		d.recordStageAction("focus", "gained")
+	case tea.BlurMsg:
+		d.recordStageAction("focus", "lost")
+	}
+}
+
+// assertReplayedView waits for the view the recording ended on
+func (d *StageDirector) assertReplayedView(path, expected string) {
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		return d.getCurrentView() == expected
+	})
+	if err != nil {
+		// The stage deadline may cut the wait short; the view is wrong either way
+		diff := unifiedDiff(path, "actual", visibleEscapes(expected), visibleEscapes(d.getCurrentView()))
+		trip := newStageTrip("REPLAY_MISMATCH", "Replayed session did not end on the recorded view", map[string]interface{}{
+			"recording": path,
+			"diff":      diff,
+		})
+		d.recordTrip(trip)
+		return
+	}
+	d.recordStageAction("assertion", "replay_final_view="+path)
+}
@@ -0,0 +1,154 @@
+package steadicam
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordSession plays msgs into model through a recorder, as a real program would
+func recordSession(t *testing.T, model tea.Model, msgs []tea.Msg) string {
+	recorder := NewRecorder()
+	for _, msg := range msgs {
+		model, _ = model.Update(recorder.Filter(model, msg))
+	}
+
+	path := filepath.Join(t.TempDir(), "session.recording.json")
+	require.NoError(t, recorder.Save(path, model))
+	return path
+}
+
+// TestRecorder_SaveAndLoad tests that recorded input survives the round trip
+func TestRecorder_SaveAndLoad(t *testing.T) {
+	input := []tea.Msg{
+		tea.WindowSizeMsg{Width: 50, Height: 10},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("pasted text"), Paste: true},
+		tea.KeyMsg{Type: tea.KeyCtrlC},
+		tea.KeyMsg{Type: tea.KeyEnter, Alt: true},
+		tea.MouseMsg{X: 4, Y: 2, Type: tea.MouseLeft, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress, Ctrl: true},
+		tea.FocusMsg{},
+		tea.BlurMsg{},
+	}
+	msgs := append([]tea.Msg{resultsMsg{source: "command output is not input"}}, input...)
+	path := recordSession(t, &mockREPLForInteractions{}, msgs)
+
+	recording, err := LoadRecording(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Mock REPL: hpasted text", recording.FinalView)
+	require.Len(t, recording.Events, len(input))
+	for i, event := range recording.Events {
+		assert.Equal(t, input[i], event.Msg(), "event %d", i)
+		if i > 0 {
+			assert.GreaterOrEqual(t, event.At, recording.Events[i-1].At)
+		}
+	}
+	assert.Equal(t, "ctrl+c", recording.Events[3].Key)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 1, "events": [{"kind": "telepathy"}]}`), 0644))
+	_, err = LoadRecording(path)
+	assert.ErrorContains(t, err, `unknown kind "telepathy"`)
+}
+
+// TestStageDirector_Replay tests replaying a recorded session as a stage
+func TestStageDirector_Replay(t *testing.T) {
+	path := recordSession(t, &mockREPLForInteractions{mode: "ready"}, []tea.Msg{
+		tea.WindowSizeMsg{Width: 50, Height: 10},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("!?"), Paste: true},
+		tea.KeyMsg{Type: tea.KeyBackspace},
+		tea.KeyMsg{Type: tea.KeyEnter},
+	})
+
+	model := &mockREPLForInteractions{mode: "ready"}
+	director := NewStageDirectorWithConfig(t, model, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().Replay(path)
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	assert.Equal(t, "hi!", director.getCurrentInput())
+	assert.Equal(t, "executed", director.getCurrentMode())
+	width, height := director.Screen().Size()
+	assert.Equal(t, [2]int{50, 10}, [2]int{width, height})
+
+	counts := map[string]int{}
+	for _, action := range director.interactions {
+		counts[action.Type]++
+	}
+	assert.Equal(t, 4, counts["keypress"])
+	assert.Equal(t, 1, counts["paste"])
+	assert.Equal(t, 1, counts["resize"])
+	assert.Equal(t, 1, counts["replay"])
+}
+
+// TestStageDirector_ReplayMouse tests that replayed clicks keep their deprecated Type
+func TestStageDirector_ReplayMouse(t *testing.T) {
+	items := []string{"alpha", "beta", "gamma"}
+	path := recordSession(t, pickerModel{items: items}, []tea.Msg{
+		mouseMsg(Point{X: 3, Y: 1}, tea.MouseButtonLeft, tea.MouseActionPress),
+		mouseMsg(Point{X: 3, Y: 1}, tea.MouseButtonLeft, tea.MouseActionRelease),
+	})
+
+	recording, err := LoadRecording(path)
+	require.NoError(t, err)
+	require.Len(t, recording.Events, 2)
+	assert.Equal(t, tea.MouseLeft, recording.Events[0].Msg().(tea.MouseMsg).Type)
+	assert.Equal(t, tea.MouseRelease, recording.Events[1].Msg().(tea.MouseMsg).Type)
+
+	// pickerModel selects on MouseLeft, so a replay without Type would select nothing
+	director := NewStageDirectorForModelWithConfig(t, pickerModel{items: items}, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().Replay(path)
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	assert.Contains(t, director.getCurrentView(), "selected: beta")
+}
+
+// TestStageDirector_ReplayMismatch tests that a different ending trips with a diff
+func TestStageDirector_ReplayMismatch(t *testing.T) {
+	path := recordSession(t, &mockREPLForInteractions{}, []tea.Msg{
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ok")},
+	})
+
+	// The fixed build no longer echoes input the way the recording did
+	model := &mockREPLForInteractions{input: "fixed:"}
+	config := testAdapterConfig
+	config.Timeout = 500 * time.Millisecond
+	director := NewStageDirectorWithConfig(t, model, config)
+	defer director.Stop()
+
+	director.Start().Replay(path)
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "REPLAY_MISMATCH", director.lastTrip.Type)
+	assert.Contains(t, director.lastTrip.Context["diff"], "-Mock REPL: ok")
+	assert.Contains(t, director.lastTrip.Context["diff"], "+Mock REPL: fixed:ok")
+}
+
+// TestStageDirector_ReplayOriginalTiming tests that pauses advance a virtual clock
+func TestStageDirector_ReplayOriginalTiming(t *testing.T) {
+	recording := Recording{Version: recordingVersion, Events: []RecordedMsg{
+		{At: 100 * time.Millisecond, Kind: "key", KeyType: tea.KeyRunes, Runes: "a"},
+		{At: 1500 * time.Millisecond, Kind: "key", KeyType: tea.KeyRunes, Runes: "b"},
+	}}
+	data, err := json.Marshal(recording)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "timed.recording.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	clock := NewFakeClock(time.Time{})
+	start := clock.Now()
+	director := NewStageDirectorWithConfig(t, &mockREPLForInteractions{}, testAdapterConfig).WithClock(clock)
+	defer director.Stop()
+
+	director.Start().ReplayWithTiming(path, OriginalTiming)
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	assert.Equal(t, "ab", director.getCurrentInput())
+	assert.Equal(t, 1500*time.Millisecond, clock.Now().Sub(start))
+}
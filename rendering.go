@@ -3,165 +3,216 @@ package steadicam
 import (
 	"image"
 	"image/color"
+	"image/draw"
 	"image/png"
+	"math"
 	"os"
-	"regexp"
 	"strings"
+	"unicode"
 
 	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/math/fixed"
 )
 
 // Config defines the visual parameters for smooth UI tracking shots
 // Kubrick's meticulous approach to camera movement and composition
 type Config struct {
-	Width       int        // Terminal width in characters
-	Height      int        // Terminal height in characters
-	FontSize    int        // Font size in pixels
-	Background  color.RGBA // Background color
-	Foreground  color.RGBA // Default text color
-	OutputDir   string     // Directory to save film frames
+	Width         int        // Terminal width in characters
+	Height        int        // Terminal height in characters
+	FontSize      int        // Font size in pixels; 0 keeps the built-in bitmap font
+	FontFile      string     // TrueType/OpenType font file; empty uses the embedded Go Mono
+	FallbackFonts []string   // Font files tried in order for glyphs FontFile lacks
+	Scale         float64    // Pixel scale for high-DPI frames, such as 2; 0 means 1
+	Background    color.RGBA // Background color
+	Foreground    color.RGBA // Default text color
+	OutputDir     string     // Directory to save film frames
 }
 
 // RenderingStage renders terminal output to image buffers with fluid motion tracking
 // Like Kubrick's revolutionary steadicam work in The Shining's hotel corridors
 type RenderingStage struct {
 	config     Config
-	buffer     [][]rune       // Character buffer
-	colorMap   [][]color.RGBA // Color information per character
+	screen     *Screen        // Virtual terminal holding the frame to capture
 	charWidth  int            // Character width in pixels
 	charHeight int            // Character height in pixels
 	font       font.Face      // Font for rendering
+	fontFamily string         // Family name of a loaded font file, for SVG captures
+	fontErr    error          // Why the configured fonts could not be loaded
+	pixel      int            // Device pixels per pixel of the bitmap font's strokes
 }
 
-// NewRenderingStage creates a camera rig that can capture smooth UI tracking shots
+// NewRenderingStage creates a camera rig that can capture smooth UI tracking shots.
+// Cell size follows the configured font; see Config. A font that fails to
+// load leaves the stage on the bitmap font and fails every CaptureFrame.
 func NewRenderingStage(config Config) *RenderingStage {
 	// Ensure output directory exists
 	if config.OutputDir != "" {
 		os.MkdirAll(config.OutputDir, 0755)
 	}
 
+	fonts, err := loadFonts(config)
+	if err != nil {
+		fonts, _ = loadFonts(Config{})
+	}
+
 	return &RenderingStage{
 		config:     config,
-		buffer:     make([][]rune, config.Height),
-		colorMap:   make([][]color.RGBA, config.Height),
-		charWidth:  8,  // Basic font character width
-		charHeight: 16, // Basic font character height
-		font:       basicfont.Face7x13, // Use basic font for now
+		screen:     NewScreen(config.Width, config.Height),
+		charWidth:  fonts.charWidth,
+		charHeight: fonts.charHeight,
+		font:       fonts.face,
+		fontFamily: fonts.family,
+		fontErr:    err,
+		pixel:      max(1, int(math.Round(config.Scale))),
 	}
 }
 
-// RenderText processes terminal output and updates the virtual buffer
+// RenderText draws a view onto a cleared virtual terminal the way BubbleTea's
+// renderer would: each line is truncated to the terminal width and lines are
+// separated by CRLF, so views taller than the terminal scroll like they do live.
 func (rs *RenderingStage) RenderText(terminalOutput string) {
-	lines := strings.Split(terminalOutput, "\n")
-
-	// Initialize or clear buffer (reuse existing allocations)
-	for i := range rs.buffer {
-		// Initialize buffer if needed
-		if rs.buffer[i] == nil {
-			rs.buffer[i] = make([]rune, rs.config.Width)
-		} else {
-			// Clear existing buffer
-			for j := range rs.buffer[i] {
-				rs.buffer[i][j] = ' '
-			}
-		}
+	rs.screen = NewScreen(rs.config.Width, rs.config.Height)
 
-		// Initialize color map if needed
-		if rs.colorMap[i] == nil {
-			rs.colorMap[i] = make([]color.RGBA, rs.config.Width)
-		} else {
-			// Clear existing colors
-			for j := range rs.colorMap[i] {
-				rs.colorMap[i][j] = rs.config.Foreground
-			}
-		}
-	}
-
-	// Process each line of terminal output
-	for lineIdx, line := range lines {
-		if lineIdx >= rs.config.Height {
-			break
+	lines := strings.Split(terminalOutput, "\n")
+	for i, line := range lines {
+		rs.screen.WriteString(truncateANSI(strings.TrimSuffix(line, "\r"), rs.config.Width))
+		if i < len(lines)-1 {
+			rs.screen.WriteString("\r\n")
 		}
-
-		// Parse ANSI escape sequences and render text
-		rs.renderLine(lineIdx, line)
 	}
 }
 
-// renderLine processes a single line with ANSI escape sequences
-func (rs *RenderingStage) renderLine(lineIdx int, line string) {
-	// Strip ANSI escape sequences for now (can be enhanced to parse colors)
-	cleanLine := rs.stripANSI(line)
-
-	for charIdx, char := range []rune(cleanLine) {
-		if charIdx >= rs.config.Width {
-			break
-		}
+// Resize changes the terminal dimensions frames are rendered at
+func (rs *RenderingStage) Resize(width, height int) {
+	rs.config.Width, rs.config.Height = width, height
+	rs.screen.Resize(width, height)
+}
 
-		rs.buffer[lineIdx][charIdx] = char
-		rs.colorMap[lineIdx][charIdx] = rs.config.Foreground
-	}
+// RenderScreen copies the state of a live virtual terminal for the next capture
+func (rs *RenderingStage) RenderScreen(screen *Screen) {
+	rs.screen = screen.Clone()
 }
 
-// stripANSI removes ANSI escape sequences
-func (rs *RenderingStage) stripANSI(text string) string {
-	// Remove common ANSI escape sequences
-	ansiRegex := regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
-	return ansiRegex.ReplaceAllString(text, "")
+// Screen returns the virtual terminal the next capture will be drawn from
+func (rs *RenderingStage) Screen() *Screen {
+	return rs.screen
 }
 
 // CaptureFrame renders the current buffer to a PNG image
 // Smooth, continuous tracking shot like Kubrick's flowing camera movements
 func (rs *RenderingStage) CaptureFrame(filename string) error {
-	width := rs.config.Width * rs.charWidth
-	height := rs.config.Height * rs.charHeight
+	if rs.fontErr != nil {
+		return rs.fontErr
+	}
+	img := rs.renderImage()
+
+	// Save to file
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return png.Encode(file, img)
+}
+
+// renderImage paints cell backgrounds and styled glyphs into a new image
+func (rs *RenderingStage) renderImage() *image.RGBA {
+	cols, rows := rs.screen.Size()
+	width := cols * rs.charWidth
+	height := rows * rs.charHeight
 
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
 
 	// Fill background
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			img.Set(x, y, rs.config.Background)
-		}
-	}
+	draw.Draw(img, img.Bounds(), image.NewUniform(rs.config.Background), image.Point{}, draw.Src)
 
-	// Draw text
 	drawer := &font.Drawer{
 		Dst:  img,
-		Src:  image.NewUniform(rs.config.Foreground),
 		Face: rs.font,
 	}
 
-	for lineIdx, line := range rs.buffer {
-		if line == nil {
-			continue
-		}
+	// Sit the baseline above the descent so glyphs stay inside their cell
+	baselineOffset := rs.charHeight - rs.font.Metrics().Descent.Ceil()
+
+	for lineIdx, line := range rs.screen.Cells() {
+		for charIdx, cell := range line {
+			grapheme, columns, style := cell.Grapheme, cell.Width, cell.Style
+			fg, bg := style.Colors(rs.config.Foreground, rs.config.Background)
+
+			x := charIdx * rs.charWidth
+			y := lineIdx * rs.charHeight
+			cell := image.Rect(x, y, x+rs.charWidth, y+rs.charHeight)
+
+			// Paint cell background when it differs from the frame background
+			if bg != rs.config.Background {
+				draw.Draw(img, cell, image.NewUniform(bg), image.Point{}, draw.Src)
+			}
 
-		for charIdx, char := range line {
-			if char == ' ' || char == 0 {
+			if style.Underline {
+				top := y + baselineOffset + rs.pixel
+				underline := image.Rect(x, top, x+rs.charWidth, top+rs.pixel)
+				draw.Draw(img, underline, image.NewUniform(fg), image.Point{}, draw.Src)
+			}
+
+			// Spacers only carry the background of the wide character before them
+			if grapheme == " " || columns == 0 {
 				continue
 			}
 
-			x := charIdx * rs.charWidth
-			y := (lineIdx + 1) * rs.charHeight
+			glyph := glyphText(grapheme)
+			width := columns * rs.charWidth
+			drawer.Src = image.NewUniform(fg)
+			rs.drawGlyph(drawer, glyph, x, width, y+baselineOffset, style.Italic)
 
-			drawer.Dot = fixed.Point26_6{
-				X: fixed.Int26_6(x << 6),
-				Y: fixed.Int26_6(y << 6),
+			// A single face has no bold weight - overstrike one stroke to the right
+			if style.Bold {
+				rs.drawGlyph(drawer, glyph, x+rs.pixel, width, y+baselineOffset, style.Italic)
 			}
+		}
+	}
+
+	return img
+}
 
-			drawer.DrawString(string(char))
+// glyphText keeps the runes of a grapheme that have glyphs to draw: the base
+// character and its combining marks, which fonts position over the base.
+// Joiners, variation selectors and the rest of an emoji sequence are left
+// out, so a sequence is drawn as its first emoji rather than as tofu.
+func glyphText(grapheme string) string {
+	var glyph strings.Builder
+	for i, r := range grapheme {
+		if i == 0 || unicode.In(r, unicode.Mn, unicode.Me) {
+			glyph.WriteRune(r)
 		}
 	}
+	return glyph.String()
+}
 
-	// Save to file
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+// drawGlyph draws a grapheme's glyphs with their baseline at (x, baseline)
+// in a cell width pixels wide.
+// Italic text is approximated by drawing the glyph in two horizontal slices,
+// shifting the upper half one stroke right.
+func (rs *RenderingStage) drawGlyph(drawer *font.Drawer, glyph string, x, width, baseline int, italic bool) {
+	img := drawer.Dst.(*image.RGBA)
+	defer func() { drawer.Dst = img }()
+
+	if !italic {
+		drawer.Dot = fixed.P(x, baseline)
+		drawer.DrawString(glyph)
+		return
 	}
-	defer file.Close()
 
-	return png.Encode(file, img)
-}
\ No newline at end of file
+	top := baseline - rs.font.Metrics().Ascent.Ceil()
+	mid := (top + baseline) / 2
+
+	// Upper half, slanted right
+	drawer.Dst = img.SubImage(image.Rect(x, top, x+width+rs.pixel, mid)).(*image.RGBA)
+	drawer.Dot = fixed.P(x+rs.pixel, baseline)
+	drawer.DrawString(glyph)
+
+	// Lower half, upright
+	drawer.Dst = img.SubImage(image.Rect(x, mid, x+width+rs.pixel, baseline+rs.font.Metrics().Descent.Ceil())).(*image.RGBA)
+	drawer.Dot = fixed.P(x, baseline)
+	drawer.DrawString(glyph)
+}
@@ -0,0 +1,164 @@
+package steadicam
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Terminal size used when a stage does not choose one
+const (
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+// terminalSize returns the configured terminal size, or the 80x24 default
+func (c StageConfig) terminalSize() (int, int) {
+	if c.hasSize() {
+		return c.Width, c.Height
+	}
+	return defaultTerminalWidth, defaultTerminalHeight
+}
+
+// hasSize reports whether the stage sends the model an explicit size
+func (c StageConfig) hasSize() bool {
+	return c.Width > 0 && c.Height > 0
+}
+
+// WithInitialSize sets the terminal size the stage starts with.
+// The model receives a tea.WindowSizeMsg right after Init and before its first
+// view is rendered, just as a real terminal reports its size before the user
+// sees anything.
+// IMPORTANT: Must be called before Start() - use Resize afterwards.
+//
+// Example:
+//
+//	director := NewStageDirector(t, model).WithInitialSize(120, 40).Start()
+func (d *StageDirector) WithInitialSize(cols, rows int) *StageDirector {
+	if d.started {
+		d.recordTrip(newStageTrip("SIZE_AFTER_START", fmt.Sprintf("WithInitialSize must be called before Start; use Resize(%d, %d) instead", cols, rows), map[string]interface{}{
+			"width":  cols,
+			"height": rows,
+		}))
+		return d
+	}
+	if !d.validSize(cols, rows) {
+		return d
+	}
+
+	d.config.Width, d.config.Height = cols, rows
+	d.screen.Resize(cols, rows)
+	return d
+}
+
+// Resize simulates the user resizing the terminal window.
+//
+// The virtual terminal takes the new dimensions, the model receives a
+// tea.WindowSizeMsg, and Resize returns once the model has processed it and
+// BubbleTea has redrawn, so assertions that follow see the new layout.
+//
+// Example:
+//
+//	director.Resize(40, 12).AssertViewContains("compact")
+func (d *StageDirector) Resize(cols, rows int) *StageDirector {
+	if !d.started {
+		return d.WithInitialSize(cols, rows)
+	}
+	if !d.validSize(cols, rows) {
+		return d
+	}
+
+	d.updateMu.Lock()
+	defer d.updateMu.Unlock()
+
+	d.config.Width, d.config.Height = cols, rows
+	d.screen.Resize(cols, rows)
+	if d.output != nil {
+		d.output.resize(cols, rows)
+	}
+
+	// WindowSizeMsg is handled by the renderer before the model, so it cannot be
+	// staged; wait for the model update that handled a size instead. Command
+	// results queued ahead of it produce updates too, and must not end the wait.
+	before := atomic.LoadInt64(&d.updateSeq)
+	d.program.Send(tea.WindowSizeMsg{Width: cols, Height: rows})
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		sized := atomic.LoadInt64(&d.sizeSeq)
+		return sized > before && atomic.LoadInt64(&d.lastProcessedSeq) >= sized
+	})
+	if err == errWaitTimeout {
+		trip := newStageTrip("RESIZE_TIMEOUT", fmt.Sprintf("Model did not process resize to %dx%d", cols, rows), map[string]interface{}{
+			"width":  cols,
+			"height": rows,
+		})
+		d.recordTrip(trip)
+		return d
+	}
+
+	d.waitForRender()
+	d.captureSnapshot("interaction")
+	d.recordStageAction("resize", fmt.Sprintf("%dx%d", cols, rows))
+	return d
+}
+
+// validSize records a trip for terminal sizes no terminal can have
+func (d *StageDirector) validSize(cols, rows int) bool {
+	if cols < 1 || rows < 1 {
+		trip := newStageTrip("INVALID_SIZE", fmt.Sprintf("Invalid terminal size %dx%d", cols, rows), map[string]interface{}{
+			"width":  cols,
+			"height": rows,
+		})
+		d.recordTrip(trip)
+		return false
+	}
+	return true
+}
+
+// initWithSize runs the model's Init and then delivers the configured size,
+// the order BubbleTea itself uses, before the program renders the first view.
+// It returns the commands both issued; ok is false when the stage has no size
+// and the program should call Init itself.
+func (d *StageDirector) initWithSize() (cmd tea.Cmd, ok bool) {
+	if !d.config.hasSize() {
+		return nil, false
+	}
+	initCmd := d.interceptCommand(d.model.Init(), nil)
+
+	msg := tea.WindowSizeMsg{Width: d.config.Width, Height: d.config.Height}
+	updated, sizeCmd := d.model.Update(msg)
+	model, isREPL := updated.(REPLModel)
+	if !isREPL {
+		d.handleInvalidModelState("Update returned non-REPLModel", msg)
+		return initCmd, true
+	}
+
+	d.model = model
+	d.modelMu.Lock()
+	d.latestModel = model
+	d.modelMu.Unlock()
+	return tea.Batch(initCmd, d.interceptCommand(sizeCmd, msg)), true
+}
+
+// sizeRenderer tells BubbleTea's renderer the initial size. The model already
+// has it, so the stage wrapper swallows this one message instead of repeating it.
+func (d *StageDirector) sizeRenderer() {
+	if !d.config.hasSize() {
+		return
+	}
+	atomic.AddInt32(&d.rendererOnlySizes, 1)
+	d.program.Send(tea.WindowSizeMsg{Width: d.config.Width, Height: d.config.Height})
+}
+
+// takeRendererOnlySize claims a pending renderer-only WindowSizeMsg, if any
+func (d *StageDirector) takeRendererOnlySize() bool {
+	for {
+		pending := atomic.LoadInt32(&d.rendererOnlySizes)
+		if pending == 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&d.rendererOnlySizes, pending, pending-1) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package steadicam
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// responsiveModel switches layout with the terminal width
+type responsiveModel struct {
+	sizes []string
+}
+
+func (m responsiveModel) Init() tea.Cmd { return nil }
+
+func (m responsiveModel) View() string {
+	if len(m.sizes) == 0 {
+		return "no size yet"
+	}
+	layout := "wide"
+	if strings.HasPrefix(m.sizes[len(m.sizes)-1], "4") {
+		layout = "compact"
+	}
+	return layout + "\nsizes: " + strings.Join(m.sizes, ",")
+}
+
+func (m responsiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.sizes = append(m.sizes, fmt.Sprintf("%dx%d", size.Width, size.Height))
+	}
+	return m, nil
+}
+
+// TestStageDirector_Resize tests initial sizing and live terminal resizes
+func TestStageDirector_Resize(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, responsiveModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	// The first view is already sized, and the size arrives exactly once
+	director.WithInitialSize(100, 30).Start().AssertViewContains("wide\nsizes: 100x30")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	cols, rows := director.Screen().Size()
+	assert.Equal(t, []int{100, 30}, []int{cols, rows})
+
+	director.Resize(40, 10).
+		AssertViewContains("compact\nsizes: 100x30,40x10").
+		AssertRegionContains(Row(0), "compact")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+	cols, rows = director.Screen().Size()
+	assert.Equal(t, []int{40, 10}, []int{cols, rows})
+
+	director.Resize(0, 10)
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "INVALID_SIZE", director.lastTrip.Type)
+}
+
+// fetchedMsg is the result of slowResizeModel's key command
+type fetchedMsg struct{}
+
+// slowResizeModel issues a command on every key, and takes a while both to
+// handle the command's result and to lay out a new size
+type slowResizeModel struct {
+	size    string
+	keys    int
+	fetched int
+}
+
+func (m slowResizeModel) Init() tea.Cmd { return nil }
+
+func (m slowResizeModel) View() string {
+	return fmt.Sprintf("size=%s keys=%d fetched=%d", m.size, m.keys, m.fetched)
+}
+
+func (m slowResizeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.keys++
+		return m, func() tea.Msg { return fetchedMsg{} }
+	case fetchedMsg:
+		time.Sleep(40 * time.Millisecond)
+		m.fetched++
+	case tea.WindowSizeMsg:
+		time.Sleep(40 * time.Millisecond)
+		m.size = fmt.Sprintf("%dx%d", msg.Width, msg.Height)
+	}
+	return m, nil
+}
+
+// TestStageDirector_ResizeBehindCommand tests that the update for a command
+// result queued ahead of the size doesn't end the wait early
+func TestStageDirector_ResizeBehindCommand(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, slowResizeModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	// The key's command has handed its result to BubbleTea, which is still handling it
+	director.Start().Type("k")
+	require.Eventually(t, func() bool { return director.PendingCommands() == 0 }, time.Second, time.Millisecond)
+
+	director.Resize(40, 10).AssertViewContains("size=40x10")
+	require.False(t, director.HasFailed(), director.getErrorMessage())
+}
+
+// TestStageDirector_UnsizedByDefault tests that stages without a size send none
+func TestStageDirector_UnsizedByDefault(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, responsiveModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().AssertViewContains("no size yet")
+	assert.False(t, director.HasFailed(), director.getErrorMessage())
+}
+
+// loadedMsg is what initOrderModel's Init command reports
+type loadedMsg struct{}
+
+// initOrderModel records the order its Init, size and Init command arrive in.
+// It is a pointer model, so state set up in Init must survive.
+type initOrderModel struct {
+	events []string
+}
+
+func (m *initOrderModel) Init() tea.Cmd {
+	m.events = append(m.events, "init")
+	return func() tea.Msg { return loadedMsg{} }
+}
+
+func (m *initOrderModel) View() string {
+	return "events: " + strings.Join(m.events, ",")
+}
+
+func (m *initOrderModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.events = append(m.events, fmt.Sprintf("size %dx%d", msg.Width, msg.Height))
+	case loadedMsg:
+		m.events = append(m.events, "loaded")
+	}
+	return m, nil
+}
+
+// TestStageDirector_InitialSizeAfterInit tests that the size follows Init, as in BubbleTea
+func TestStageDirector_InitialSizeAfterInit(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, &initOrderModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	director.WithInitialSize(80, 24).Start().WaitForText("loaded").
+		AssertViewContains("events: init,size 80x24,loaded")
+	assert.False(t, director.HasFailed(), director.getErrorMessage())
+}
+
+// TestStageDirector_InitialSizeAfterStart tests that sizing a running stage trips
+func TestStageDirector_InitialSizeAfterStart(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, responsiveModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	director.Start().WithInitialSize(100, 30)
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "SIZE_AFTER_START", director.lastTrip.Type)
+	director.AssertViewContains("no size yet")
+}
+
+// TestOperator_ResizeKeepsCameraInSync tests that frames follow the terminal size
+func TestOperator_ResizeKeepsCameraInSync(t *testing.T) {
+	op := NewOperatorForModel(t, responsiveModel{}, t.TempDir()).WithInitialSize(60, 20)
+	defer op.Stop()
+	assert.Equal(t, 60, op.renderingStage.config.Width)
+
+	op.Start().Resize(44, 12).CaptureTrackingShot("compact")
+	require.False(t, op.HasFailed(), op.getErrorMessage())
+	assert.Equal(t, 44, op.renderingStage.config.Width)
+	assert.Equal(t, 12, op.renderingStage.config.Height)
+
+	cols, rows := op.renderingStage.Screen().Size()
+	assert.Equal(t, []int{44, 12}, []int{cols, rows})
+}
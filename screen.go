@@ -0,0 +1,962 @@
+package steadicam
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Cell is a single character position on the virtual terminal screen.
+//
+// A cell holds a whole grapheme cluster, so a letter with combining accents or
+// an emoji ZWJ sequence stays one character. Wide characters such as CJK and
+// most emoji cover two cells: the first holds the grapheme with Width 2, the
+// second is a spacer with an empty Grapheme and Width 0.
+type Cell struct {
+	Grapheme string    // Grapheme cluster drawn in the cell, " " when blank
+	Width    int       // Columns the grapheme covers: 1, 2 when wide, 0 for a wide character's spacer
+	Style    CellStyle // SGR attributes the character was drawn with
+}
+
+// emptyCell is an erased cell with the default style
+var emptyCell = Cell{Grapheme: " ", Width: 1}
+
+// IsSpacer reports whether the cell is the right half of a wide character
+func (c Cell) IsSpacer() bool {
+	return c.Width == 0
+}
+
+// Screen is an in-process VT100/xterm screen model.
+//
+// It implements io.Writer so BubbleTea's real renderer can draw into it, and
+// keeps a cell grid, cursor position, scroll region, SGR pen, and separate main
+// and alternate screen buffers. Captures, assertions and HTML reports all read
+// from this single screen state instead of re-parsing escape sequences.
+//
+// The projection booth - whatever the renderer projects, the screen shows.
+//
+// Supported controls include cursor movement (CUU/CUD/CUF/CUB, CUP, CHA, VPA,
+// CNL/CPL), erase (ED, EL, ECH), insert/delete (ICH, DCH, IL, DL), scrolling
+// (SU, SD, IND, RI, DECSTBM), cursor save/restore, SGR, autowrap, newline mode,
+// cursor visibility, and the 47/1047/1049 alternate screen modes. OSC, DCS and
+// other string sequences are consumed and ignored.
+type Screen struct {
+	mu sync.RWMutex
+
+	width  int
+	height int
+
+	main      [][]Cell
+	alt       [][]Cell
+	altActive bool
+
+	cursorX     int
+	cursorY     int
+	wrapPending bool // Cursor sits past the last column waiting to wrap
+	cursorShown bool
+
+	savedX   int
+	savedY   int
+	savedPen CellStyle
+
+	scrollTop    int // First row of the scroll region (inclusive)
+	scrollBottom int // Last row of the scroll region (inclusive)
+
+	pen         CellStyle
+	autowrap    bool
+	newlineMode bool // LNM: line feed also returns the carriage
+
+	pending  string    // Incomplete escape or UTF-8 sequence from the last write
+	joinable bool      // The last thing written was a character that later runes may extend
+	joinX    int       // Cell holding that character
+	joinY    int
+	drawn    bool      // Set when the current write touches any cell
+	lastDraw time.Time // When a write last drew or erased cells
+}
+
+// NewScreen creates a blank screen of the given size in characters
+func NewScreen(width, height int) *Screen {
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	s := &Screen{
+		width:  width,
+		height: height,
+	}
+	s.reset()
+	return s
+}
+
+// reset restores power-on state. Caller must hold the lock.
+func (s *Screen) reset() {
+	s.main = newCellGrid(s.width, s.height)
+	s.alt = newCellGrid(s.width, s.height)
+	s.altActive = false
+	s.cursorX, s.cursorY = 0, 0
+	s.wrapPending = false
+	s.cursorShown = true
+	s.savedX, s.savedY, s.savedPen = 0, 0, CellStyle{}
+	s.scrollTop, s.scrollBottom = 0, s.height-1
+	s.pen = CellStyle{}
+	s.autowrap = true
+	s.newlineMode = false
+	s.pending = ""
+	s.joinable = false
+}
+
+// newCellGrid allocates a blank grid of cells
+func newCellGrid(width, height int) [][]Cell {
+	grid := make([][]Cell, height)
+	for y := range grid {
+		grid[y] = newCellRow(width)
+	}
+	return grid
+}
+
+// newCellRow allocates a blank row of cells
+func newCellRow(width int) []Cell {
+	row := make([]Cell, width)
+	for x := range row {
+		row[x] = emptyCell
+	}
+	return row
+}
+
+// Reset clears the screen and restores power-on state (RIS)
+func (s *Screen) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+}
+
+// Resize changes the screen dimensions, keeping content anchored top-left
+func (s *Screen) Resize(width, height int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if width < 1 {
+		width = 1
+	}
+	if height < 1 {
+		height = 1
+	}
+
+	s.main = resizeCellGrid(s.main, width, height)
+	s.alt = resizeCellGrid(s.alt, width, height)
+	s.width, s.height = width, height
+	s.scrollTop, s.scrollBottom = 0, height-1
+	s.cursorX = clampInt(s.cursorX, 0, width-1)
+	s.cursorY = clampInt(s.cursorY, 0, height-1)
+	s.wrapPending = false
+	s.joinable = false
+}
+
+// resizeCellGrid copies a grid into a new one of the given size
+func resizeCellGrid(grid [][]Cell, width, height int) [][]Cell {
+	resized := newCellGrid(width, height)
+	for y := 0; y < height && y < len(grid); y++ {
+		copy(resized[y], grid[y])
+		repairWide(resized[y], 0, width) // A narrower screen can cut a wide character in half
+	}
+	return resized
+}
+
+// Write feeds terminal output into the screen. It never returns an error;
+// escape sequences split across writes are buffered until complete.
+func (s *Screen) Write(p []byte) (int, error) {
+	s.WriteString(string(p))
+	return len(p), nil
+}
+
+// WriteString feeds terminal output into the screen
+func (s *Screen) WriteString(output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data := s.pending + output
+	s.pending = ""
+	s.drawn = false
+
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b == 0x1b:
+			end, complete := scanEscape(data, i)
+			if !complete {
+				s.pending = data[i:]
+				i = len(data)
+				continue
+			}
+			s.joinable = false
+			s.handleEscape(data[i:end])
+			i = end
+		case b < 0x20 || b == 0x7f:
+			s.joinable = false
+			s.handleControl(b)
+			i++
+		default:
+			if !utf8.FullRuneInString(data[i:]) {
+				s.pending = data[i:]
+				i = len(data)
+				continue
+			}
+			char, size := utf8.DecodeRuneInString(data[i:])
+			s.print(char)
+			i += size
+		}
+	}
+
+	// Mode switches such as hiding the cursor don't count as a new frame
+	if s.drawn {
+		s.lastDraw = time.Now()
+	}
+}
+
+// scanEscape finds the end of the escape sequence starting at s[start].
+// It returns the index just past the sequence and whether the sequence is
+// complete; incomplete sequences can be resumed once more output arrives.
+func scanEscape(s string, start int) (int, bool) {
+	i := start + 1
+	if i >= len(s) {
+		return len(s), false
+	}
+
+	switch s[i] {
+	case 0x1b: // A new escape cancels the unfinished one
+		return i, true
+	case '[': // CSI: parameter and intermediate bytes, then a final byte
+		i++
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x3f {
+			i++
+		}
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		if i >= len(s) {
+			return len(s), false
+		}
+		if s[i] >= 0x40 && s[i] <= 0x7e {
+			return i + 1, true
+		}
+		return i, true // Malformed - abandon the sequence at the offending byte
+	case ']', 'P', '_', '^', 'X': // String sequences: OSC, DCS, APC, PM, SOS
+		for i++; i < len(s); i++ {
+			if s[i] == '\a' && s[start+1] == ']' {
+				return i + 1, true
+			}
+			if s[i] == 0x1b {
+				if i+1 >= len(s) {
+					return len(s), false
+				}
+				if s[i+1] == '\\' {
+					return i + 2, true
+				}
+			}
+		}
+		return len(s), false
+	case '(', ')', '*', '+', '#', '%', ' ': // Three-byte designations
+		if i+1 >= len(s) {
+			return len(s), false
+		}
+		return i + 2, true
+	default:
+		return i + 1, true
+	}
+}
+
+// handleControl executes a C0 control character
+func (s *Screen) handleControl(b byte) {
+	switch b {
+	case '\r':
+		s.cursorX = 0
+		s.wrapPending = false
+	case '\n', '\v', '\f':
+		if s.newlineMode {
+			s.cursorX = 0
+		}
+		s.lineFeed()
+	case '\b':
+		if s.cursorX > 0 {
+			s.cursorX--
+		}
+		s.wrapPending = false
+	case '\t':
+		s.cursorX = min((s.cursorX/8+1)*8, s.width-1)
+		s.wrapPending = false
+	}
+}
+
+// handleEscape executes a complete escape sequence
+func (s *Screen) handleEscape(seq string) {
+	if len(seq) < 2 {
+		return
+	}
+
+	switch seq[1] {
+	case '[':
+		if len(seq) > 2 {
+			s.handleCSI(seq[2:len(seq)-1], seq[len(seq)-1])
+		}
+	case '7': // DECSC
+		s.saveCursor()
+	case '8': // DECRC
+		s.restoreCursor()
+	case 'D': // IND
+		s.lineFeed()
+	case 'E': // NEL
+		s.cursorX = 0
+		s.lineFeed()
+	case 'M': // RI
+		s.reverseIndex()
+	case 'c': // RIS
+		s.reset()
+	}
+}
+
+// handleCSI executes a control sequence with its parameter string and final byte
+func (s *Screen) handleCSI(params string, final byte) {
+	// Only DEC private ('?') sequences are understood; other prefixed
+	// sequences are xterm extensions that don't affect the screen
+	private := false
+	if params != "" && strings.IndexByte("?<=>", params[0]) >= 0 {
+		if params[0] != '?' {
+			return
+		}
+		private = true
+		params = params[1:]
+	}
+	params = strings.TrimRight(params, " !\"#$%&'()*+,-./")
+
+	if final == 'm' {
+		if !private {
+			s.pen.applySGR(parseSGRParams(params))
+		}
+		return
+	}
+
+	args := parseCSIParams(params)
+	n := csiParam(args, 0, 1)
+
+	switch final {
+	case 'A': // CUU
+		s.moveCursor(s.cursorX, s.cursorY-n, true)
+	case 'B', 'e': // CUD, VPR
+		s.moveCursor(s.cursorX, s.cursorY+n, true)
+	case 'C', 'a': // CUF, HPR
+		s.moveCursor(s.cursorX+n, s.cursorY, false)
+	case 'D': // CUB
+		s.moveCursor(s.cursorX-n, s.cursorY, false)
+	case 'E': // CNL
+		s.moveCursor(0, s.cursorY+n, true)
+	case 'F': // CPL
+		s.moveCursor(0, s.cursorY-n, true)
+	case 'G', '`': // CHA, HPA
+		s.moveCursor(n-1, s.cursorY, false)
+	case 'd': // VPA
+		s.moveCursor(s.cursorX, n-1, false)
+	case 'H', 'f': // CUP, HVP
+		s.moveCursor(csiParam(args, 1, 1)-1, n-1, false)
+	case 'J': // ED
+		s.eraseDisplay(csiParam(args, 0, 0))
+	case 'K': // EL
+		s.eraseLine(csiParam(args, 0, 0))
+	case 'X': // ECH
+		s.eraseCells(s.cursorY, s.cursorX, s.cursorX+n)
+	case '@': // ICH
+		s.insertCells(n)
+	case 'P': // DCH
+		s.deleteCells(n)
+	case 'L': // IL
+		s.insertLines(n)
+	case 'M': // DL
+		s.deleteLines(n)
+	case 'S': // SU
+		s.scrollUp(s.scrollTop, s.scrollBottom, n)
+	case 'T': // SD
+		if !private {
+			s.scrollDown(s.scrollTop, s.scrollBottom, n)
+		}
+	case 'r': // DECSTBM
+		if !private {
+			s.setScrollRegion(csiParam(args, 0, 1)-1, csiParam(args, 1, s.height)-1)
+		}
+	case 's': // SCOSC
+		s.saveCursor()
+	case 'u': // SCORC
+		s.restoreCursor()
+	case 'h':
+		s.setModes(args, private, true)
+	case 'l':
+		s.setModes(args, private, false)
+	}
+}
+
+// parseCSIParams parses ';' separated numeric parameters; missing values are -1
+func parseCSIParams(params string) []int {
+	if params == "" {
+		return nil
+	}
+
+	fields := strings.Split(params, ";")
+	args := make([]int, len(fields))
+	for i, field := range fields {
+		if colon := strings.IndexByte(field, ':'); colon >= 0 {
+			field = field[:colon]
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || field == "" {
+			n = -1
+		}
+		args[i] = n
+	}
+	return args
+}
+
+// csiParam returns the parameter at index, or def when absent or zero-valued
+// (for counts) as terminals treat 0 and missing the same way
+func csiParam(args []int, index, def int) int {
+	if index >= len(args) || args[index] < 0 {
+		return def
+	}
+	if args[index] == 0 && def > 0 {
+		return def
+	}
+	return args[index]
+}
+
+// setModes handles SM/RM and DECSET/DECRST
+func (s *Screen) setModes(args []int, private, enable bool) {
+	for _, mode := range args {
+		if !private {
+			if mode == 20 {
+				s.newlineMode = enable
+			}
+			continue
+		}
+
+		switch mode {
+		case 7:
+			s.autowrap = enable
+		case 25:
+			s.cursorShown = enable
+		case 47, 1047:
+			s.switchScreen(enable, false)
+		case 1049:
+			s.switchScreen(enable, true)
+		}
+	}
+}
+
+// switchScreen enters or leaves the alternate screen buffer
+func (s *Screen) switchScreen(alt, saveCursor bool) {
+	if alt == s.altActive {
+		return
+	}
+
+	if alt {
+		if saveCursor {
+			s.saveCursor()
+		}
+		s.altActive = true
+		s.alt = newCellGrid(s.width, s.height)
+		return
+	}
+
+	s.altActive = false
+	if saveCursor {
+		s.restoreCursor()
+	}
+}
+
+// cells returns the active buffer. Caller must hold the lock.
+func (s *Screen) cells() [][]Cell {
+	if s.altActive {
+		return s.alt
+	}
+	return s.main
+}
+
+// print draws a character at the cursor and advances it by the character's
+// width. Runes that continue the previous grapheme cluster - combining marks,
+// variation selectors, zero-width joiners and what they join - are added to
+// the cell that cluster is in instead of taking a cell of their own.
+func (s *Screen) print(char rune) {
+	if s.joinable && s.extendGrapheme(char) {
+		return
+	}
+
+	grapheme := string(char)
+	width := uniseg.StringWidth(grapheme)
+	if width == 0 {
+		return // A zero-width character with nothing to attach to takes no space
+	}
+	if width > s.width {
+		return // No room for a wide character on a one-column screen
+	}
+
+	if s.wrapPending {
+		s.cursorX = 0
+		s.lineFeed()
+	}
+	if s.cursorX+width > s.width {
+		// A wide character that doesn't fit wraps whole, or is dropped without autowrap
+		if !s.autowrap {
+			return
+		}
+		s.eraseCells(s.cursorY, s.cursorX, s.width)
+		s.cursorX = 0
+		s.lineFeed()
+	}
+
+	s.putGrapheme(s.cursorX, s.cursorY, grapheme, width)
+	s.joinable, s.joinX, s.joinY = true, s.cursorX, s.cursorY
+	s.advance(width)
+}
+
+// extendGrapheme adds char to the grapheme last printed when the two form one
+// cluster. A cluster that grows wide, such as a symbol followed by the emoji
+// presentation selector, takes the next cell too when the cursor is still on it.
+func (s *Screen) extendGrapheme(char rune) bool {
+	cell := s.cells()[s.joinY][s.joinX]
+	joined := cell.Grapheme + string(char)
+	if _, rest, width, _ := uniseg.FirstGraphemeClusterInString(joined, -1); rest != "" {
+		return false
+	} else if width > cell.Width {
+		next := s.joinX + cell.Width
+		if width == 2 && next < s.width && s.cursorY == s.joinY && s.cursorX == next && !s.wrapPending {
+			s.putGrapheme(s.joinX, s.joinY, joined, width)
+			s.advance(1)
+			return true
+		}
+	}
+
+	s.cells()[s.joinY][s.joinX].Grapheme = joined
+	s.drawn = true
+	return true
+}
+
+// putGrapheme draws a grapheme at (x, y) with the pen, blanking any wide
+// character it overwrites half of
+func (s *Screen) putGrapheme(x, y int, grapheme string, width int) {
+	row := s.cells()[y]
+	row[x] = Cell{Grapheme: grapheme, Width: width, Style: s.pen}
+	if width == 2 {
+		row[x+1] = Cell{Style: s.pen}
+	}
+	repairWide(row, x, x+width)
+	s.drawn = true
+}
+
+// advance moves the cursor past a character of the given width, holding it on
+// the last column until the next character wraps
+func (s *Screen) advance(width int) {
+	if s.cursorX+width >= s.width {
+		s.cursorX = s.width - 1
+		s.wrapPending = s.autowrap
+		return
+	}
+	s.cursorX += width
+}
+
+// repairWide blanks the orphaned halves of wide characters left around
+// columns from..to-1 of a row after they were overwritten, erased or shifted
+func repairWide(row []Cell, from, to int) {
+	for x := max(from-1, 0); x <= to && x < len(row); x++ {
+		cell := row[x]
+		lead := cell.Width == 2 && (x+1 >= len(row) || !row[x+1].IsSpacer())
+		spacer := cell.IsSpacer() && (x == 0 || row[x-1].Width != 2)
+		if lead || spacer {
+			row[x] = Cell{Grapheme: " ", Width: 1, Style: cell.Style}
+		}
+	}
+}
+
+// moveCursor positions the cursor, clamping to the screen. Relative vertical
+// movement stops at the scroll margins when the cursor starts inside them.
+func (s *Screen) moveCursor(x, y int, withinMargins bool) {
+	top, bottom := 0, s.height-1
+	if withinMargins && s.cursorY >= s.scrollTop && s.cursorY <= s.scrollBottom {
+		top, bottom = s.scrollTop, s.scrollBottom
+	}
+
+	s.cursorX = clampInt(x, 0, s.width-1)
+	s.cursorY = clampInt(y, top, bottom)
+	s.wrapPending = false
+}
+
+// lineFeed moves the cursor down, scrolling the region at its bottom margin
+func (s *Screen) lineFeed() {
+	s.wrapPending = false
+	if s.cursorY == s.scrollBottom {
+		s.scrollUp(s.scrollTop, s.scrollBottom, 1)
+		return
+	}
+	if s.cursorY < s.height-1 {
+		s.cursorY++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling the region at its top margin
+func (s *Screen) reverseIndex() {
+	s.wrapPending = false
+	if s.cursorY == s.scrollTop {
+		s.scrollDown(s.scrollTop, s.scrollBottom, 1)
+		return
+	}
+	if s.cursorY > 0 {
+		s.cursorY--
+	}
+}
+
+// scrollUp moves rows top..bottom up by n, blanking the rows uncovered at the bottom
+func (s *Screen) scrollUp(top, bottom, n int) {
+	s.drawn = true
+	grid := s.cells()
+	n = min(n, bottom-top+1)
+	copy(grid[top:bottom+1], grid[top+n:bottom+1])
+	for y := bottom - n + 1; y <= bottom; y++ {
+		grid[y] = s.blankRow()
+	}
+}
+
+// scrollDown moves rows top..bottom down by n, blanking the rows uncovered at the top
+func (s *Screen) scrollDown(top, bottom, n int) {
+	s.drawn = true
+	grid := s.cells()
+	n = min(n, bottom-top+1)
+	copy(grid[top+n:bottom+1], grid[top:bottom+1-n])
+	for y := top; y < top+n; y++ {
+		grid[y] = s.blankRow()
+	}
+}
+
+// blankCell returns an erased cell using the pen's background (xterm's BCE behavior)
+func (s *Screen) blankCell() Cell {
+	return Cell{Grapheme: " ", Width: 1, Style: CellStyle{Background: s.pen.Background}}
+}
+
+// blankRow returns a new erased row
+func (s *Screen) blankRow() []Cell {
+	row := make([]Cell, s.width)
+	blank := s.blankCell()
+	for x := range row {
+		row[x] = blank
+	}
+	return row
+}
+
+// eraseCells blanks columns from..to-1 of a row
+func (s *Screen) eraseCells(y, from, to int) {
+	s.drawn = true
+	row := s.cells()[y]
+	blank := s.blankCell()
+	for x := max(from, 0); x < to && x < s.width; x++ {
+		row[x] = blank
+	}
+	repairWide(row, from, to)
+}
+
+// eraseLine implements EL
+func (s *Screen) eraseLine(mode int) {
+	switch mode {
+	case 0:
+		s.eraseCells(s.cursorY, s.cursorX, s.width)
+	case 1:
+		s.eraseCells(s.cursorY, 0, s.cursorX+1)
+	case 2:
+		s.eraseCells(s.cursorY, 0, s.width)
+	}
+}
+
+// eraseDisplay implements ED
+func (s *Screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.eraseCells(s.cursorY, s.cursorX, s.width)
+		for y := s.cursorY + 1; y < s.height; y++ {
+			s.eraseCells(y, 0, s.width)
+		}
+	case 1:
+		for y := 0; y < s.cursorY; y++ {
+			s.eraseCells(y, 0, s.width)
+		}
+		s.eraseCells(s.cursorY, 0, s.cursorX+1)
+	case 2, 3:
+		for y := 0; y < s.height; y++ {
+			s.eraseCells(y, 0, s.width)
+		}
+	}
+}
+
+// insertCells implements ICH, shifting the rest of the line right
+func (s *Screen) insertCells(n int) {
+	row := s.cells()[s.cursorY]
+	n = min(n, s.width-s.cursorX)
+	copy(row[s.cursorX+n:], row[s.cursorX:s.width-n])
+	s.eraseCells(s.cursorY, s.cursorX, s.cursorX+n)
+	repairWide(row, 0, s.width)
+	s.wrapPending = false
+}
+
+// deleteCells implements DCH, shifting the rest of the line left
+func (s *Screen) deleteCells(n int) {
+	row := s.cells()[s.cursorY]
+	n = min(n, s.width-s.cursorX)
+	copy(row[s.cursorX:], row[s.cursorX+n:])
+	s.eraseCells(s.cursorY, s.width-n, s.width)
+	repairWide(row, 0, s.width)
+	s.wrapPending = false
+}
+
+// insertLines implements IL within the scroll region
+func (s *Screen) insertLines(n int) {
+	if s.cursorY < s.scrollTop || s.cursorY > s.scrollBottom {
+		return
+	}
+	s.scrollDown(s.cursorY, s.scrollBottom, n)
+	s.cursorX = 0
+	s.wrapPending = false
+}
+
+// deleteLines implements DL within the scroll region
+func (s *Screen) deleteLines(n int) {
+	if s.cursorY < s.scrollTop || s.cursorY > s.scrollBottom {
+		return
+	}
+	s.scrollUp(s.cursorY, s.scrollBottom, n)
+	s.cursorX = 0
+	s.wrapPending = false
+}
+
+// setScrollRegion implements DECSTBM and homes the cursor
+func (s *Screen) setScrollRegion(top, bottom int) {
+	top = clampInt(top, 0, s.height-1)
+	bottom = clampInt(bottom, 0, s.height-1)
+	if top >= bottom {
+		top, bottom = 0, s.height-1
+	}
+	s.scrollTop, s.scrollBottom = top, bottom
+	s.moveCursor(0, 0, false)
+}
+
+// saveCursor implements DECSC
+func (s *Screen) saveCursor() {
+	s.savedX, s.savedY, s.savedPen = s.cursorX, s.cursorY, s.pen
+}
+
+// restoreCursor implements DECRC
+func (s *Screen) restoreCursor() {
+	s.pen = s.savedPen
+	s.moveCursor(s.savedX, s.savedY, false)
+}
+
+// Size returns the screen dimensions in characters
+func (s *Screen) Size() (width, height int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.width, s.height
+}
+
+// Cursor returns the zero-based cursor column and row
+func (s *Screen) Cursor() (x, y int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cursorX, s.cursorY
+}
+
+// CursorVisible reports whether the cursor is shown (DECTCEM)
+func (s *Screen) CursorVisible() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cursorShown
+}
+
+// AltScreen reports whether the alternate screen buffer is active
+func (s *Screen) AltScreen() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.altActive
+}
+
+// LastDraw returns when output last drew or erased cells on the screen
+func (s *Screen) LastDraw() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastDraw
+}
+
+// Cell returns the cell at column x, row y of the active buffer
+func (s *Screen) Cell(x, y int) Cell {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if x < 0 || y < 0 || x >= s.width || y >= s.height {
+		return emptyCell
+	}
+	return s.cells()[y][x]
+}
+
+// Cells returns a copy of the active buffer's cell grid
+func (s *Screen) Cells() [][]Cell {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	grid := s.cells()
+	copied := make([][]Cell, len(grid))
+	for y, row := range grid {
+		copied[y] = append([]Cell(nil), row...)
+	}
+	return copied
+}
+
+// Line returns the plain text of row y with trailing blanks removed
+func (s *Screen) Line(y int) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if y < 0 || y >= s.height {
+		return ""
+	}
+	return rowText(s.cells()[y])
+}
+
+// Lines returns the plain text of every row
+func (s *Screen) Lines() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lines := make([]string, s.height)
+	for y, row := range s.cells() {
+		lines[y] = rowText(row)
+	}
+	return lines
+}
+
+// String returns the screen as plain text, without trailing blank lines
+func (s *Screen) String() string {
+	lines := s.Lines()
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Clone returns an independent copy of the screen
+func (s *Screen) Clone() *Screen {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &Screen{
+		width:        s.width,
+		height:       s.height,
+		main:         resizeCellGrid(s.main, s.width, s.height),
+		alt:          resizeCellGrid(s.alt, s.width, s.height),
+		altActive:    s.altActive,
+		cursorX:      s.cursorX,
+		cursorY:      s.cursorY,
+		wrapPending:  s.wrapPending,
+		cursorShown:  s.cursorShown,
+		savedX:       s.savedX,
+		savedY:       s.savedY,
+		savedPen:     s.savedPen,
+		scrollTop:    s.scrollTop,
+		scrollBottom: s.scrollBottom,
+		pen:          s.pen,
+		autowrap:     s.autowrap,
+		newlineMode:  s.newlineMode,
+		pending:      s.pending,
+		joinable:     s.joinable,
+		joinX:        s.joinX,
+		joinY:        s.joinY,
+		lastDraw:     s.lastDraw,
+	}
+}
+
+// rowText converts a row of cells to text with trailing blanks removed.
+// Wide characters appear once; their spacer cells add nothing.
+func rowText(row []Cell) string {
+	var line strings.Builder
+	for _, cell := range row {
+		line.WriteString(cell.Grapheme)
+	}
+	return strings.TrimRight(line.String(), " ")
+}
+
+// stripANSI removes escape sequences from text, leaving printable content
+func stripANSI(text string) string {
+	if !strings.Contains(text, "\x1b") {
+		return text
+	}
+
+	var plain strings.Builder
+	for i := 0; i < len(text); {
+		if text[i] == 0x1b {
+			i, _ = scanEscape(text, i)
+			continue
+		}
+		plain.WriteByte(text[i])
+		i++
+	}
+	return plain.String()
+}
+
+// truncateANSI cuts a line to width columns, keeping every escape sequence
+// so styles opened before the cut are still closed after it. This mirrors
+// BubbleTea's renderer, which truncates rather than wraps, measuring each
+// grapheme cluster's display width; a wide character that would straddle
+// the edge is dropped.
+func truncateANSI(line string, width int) string {
+	var truncated strings.Builder
+	col := 0
+	for i := 0; i < len(line); {
+		if line[i] == 0x1b {
+			end, _ := scanEscape(line, i)
+			truncated.WriteString(line[i:end])
+			i = end
+			continue
+		}
+
+		// Segment the text up to the next escape into grapheme clusters
+		end := strings.IndexByte(line[i:], 0x1b)
+		if end < 0 {
+			end = len(line)
+		} else {
+			end += i
+		}
+		for text, state := line[i:end], -1; text != ""; {
+			var cluster string
+			var w int
+			cluster, text, w, state = uniseg.FirstGraphemeClusterInString(text, state)
+			if col+w <= width {
+				truncated.WriteString(cluster)
+			}
+			col += w
+		}
+		i = end
+	}
+	return truncated.String()
+}
+
+// displayWidth returns the number of terminal columns text covers
+func displayWidth(text string) int {
+	return uniseg.StringWidth(text)
+}
+
+// clampInt limits n to the range lo..hi
+func clampInt(n, lo, hi int) int {
+	if n < lo {
+		return lo
+	}
+	if n > hi {
+		return hi
+	}
+	return n
+}
@@ -0,0 +1,206 @@
+package steadicam
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScreen_PrintAndWrap tests printing, carriage control and autowrap
+func TestScreen_PrintAndWrap(t *testing.T) {
+	screen := NewScreen(5, 3)
+
+	screen.WriteString("hello world")
+	assert.Equal(t, []string{"hello", " worl", "d"}, screen.Lines())
+
+	x, y := screen.Cursor()
+	assert.Equal(t, 1, x)
+	assert.Equal(t, 2, y)
+
+	// A bare line feed keeps the column; carriage return goes home
+	screen.Reset()
+	screen.WriteString("ab\ncd\r\nef")
+	assert.Equal(t, []string{"ab", "  cd", "ef"}, screen.Lines())
+
+	// Newline mode turns line feeds into CRLF
+	screen.Reset()
+	screen.WriteString("\x1b[20hab\ncd")
+	assert.Equal(t, []string{"ab", "cd", ""}, screen.Lines())
+}
+
+// TestScreen_CursorAndErase tests absolute positioning and erase commands
+func TestScreen_CursorAndErase(t *testing.T) {
+	screen := NewScreen(10, 4)
+
+	screen.WriteString("aaaaaaaaaa\r\nbbbbbbbbbb\r\ncccccccccc\r\ndddddddddd")
+
+	screen.WriteString("\x1b[2;3H\x1b[K") // Erase to end of line from row 2, col 3
+	assert.Equal(t, "bb", screen.Line(1))
+
+	screen.WriteString("\x1b[3;5H\x1b[1K") // Erase to start of line
+	assert.Equal(t, "     ccccc", screen.Line(2))
+
+	screen.WriteString("\x1b[1;4H\x1b[2X") // Erase two characters
+	assert.Equal(t, "aaa  aaaaa", screen.Line(0))
+
+	screen.WriteString("\x1b[4;1H\x1b[3P") // Delete three characters
+	assert.Equal(t, "ddddddd", screen.Line(3))
+
+	screen.WriteString("\x1b[4;1H\x1b[2@") // Insert two blanks
+	assert.Equal(t, "  ddddddd", screen.Line(3))
+
+	screen.WriteString("\x1b[2;1H\x1b[J") // Erase below
+	assert.Equal(t, "aaa  aaaaa", screen.String())
+
+	screen.WriteString("\x1b[2J")
+	assert.Equal(t, "", screen.String())
+}
+
+// TestScreen_ScrollRegion tests scrolling inside DECSTBM margins
+func TestScreen_ScrollRegion(t *testing.T) {
+	screen := NewScreen(6, 5)
+	screen.WriteString("\x1b[20hheader\n1\n2\n3\nfooter")
+
+	// Scroll only rows 2-4, leaving header and footer in place
+	screen.WriteString("\x1b[2;4r\x1b[4;1H\nnew")
+	assert.Equal(t, []string{"header", "2", "3", "new", "footer"}, screen.Lines())
+
+	// Reverse index at the top margin scrolls the region down
+	screen.WriteString("\x1b[2;1H\x1bMtop")
+	assert.Equal(t, []string{"header", "top", "2", "3", "footer"}, screen.Lines())
+
+	// Insert and delete lines stay within the region
+	screen.WriteString("\x1b[3;1H\x1b[M")
+	assert.Equal(t, []string{"header", "top", "3", "", "footer"}, screen.Lines())
+	screen.WriteString("\x1b[2;1H\x1b[L")
+	assert.Equal(t, []string{"header", "", "top", "3", "footer"}, screen.Lines())
+}
+
+// TestScreen_AltScreen tests switching to and from the alternate buffer
+func TestScreen_AltScreen(t *testing.T) {
+	screen := NewScreen(10, 3)
+	screen.WriteString("main text")
+
+	screen.WriteString("\x1b[?1049h")
+	assert.True(t, screen.AltScreen())
+	assert.Equal(t, "", screen.String())
+
+	screen.WriteString("\x1b[Halt text")
+	assert.Equal(t, "alt text", screen.String())
+
+	screen.WriteString("\x1b[?1049l")
+	assert.False(t, screen.AltScreen())
+	assert.Equal(t, "main text", screen.String())
+
+	x, y := screen.Cursor()
+	assert.Equal(t, 9, x, "cursor restored after leaving alt screen")
+	assert.Equal(t, 0, y)
+}
+
+// TestScreen_SplitSequences tests escape and UTF-8 sequences split across writes
+func TestScreen_SplitSequences(t *testing.T) {
+	screen := NewScreen(10, 2)
+
+	screen.Write([]byte("\x1b[3"))
+	screen.Write([]byte("1mred\x1b"))
+	screen.Write([]byte("[0m \xc3"))
+	screen.Write([]byte("\xa9"))
+
+	assert.Equal(t, "red é", screen.Line(0))
+	assert.Equal(t, ansiPalette[1], screen.Cell(0, 0).Style.Foreground.RGBA)
+	assert.Equal(t, CellStyle{}, screen.Cell(4, 0).Style)
+
+	// OSC sequences such as window titles are ignored
+	screen.WriteString("\r\n\x1b]0;title\a\x1b[?25lok")
+	assert.Equal(t, "ok", screen.Line(1))
+	assert.False(t, screen.CursorVisible())
+}
+
+// TestScreen_WideCharacters tests wide characters, grapheme clusters and the cursor
+func TestScreen_WideCharacters(t *testing.T) {
+	screen := NewScreen(8, 3)
+
+	// Wide characters take two columns; the second cell is a spacer
+	screen.WriteString("漢字ab")
+	assert.Equal(t, "漢字ab", screen.Line(0))
+	assert.Equal(t, Cell{Grapheme: "漢", Width: 2}, screen.Cell(0, 0))
+	assert.True(t, screen.Cell(1, 0).IsSpacer())
+	assert.Equal(t, "a", screen.Cell(4, 0).Grapheme)
+	x, _ := screen.Cursor()
+	assert.Equal(t, 6, x)
+
+	// Combining marks, emoji sequences and flags stay one character
+	screen.Reset()
+	screen.WriteString("e\u0301|👩\u200d💻|🇯🇵|")
+	assert.Equal(t, "e\u0301|👩\u200d💻|🇯🇵|", screen.Line(0))
+	assert.Equal(t, Cell{Grapheme: "e\u0301", Width: 1}, screen.Cell(0, 0))
+	assert.Equal(t, Cell{Grapheme: "👩\u200d💻", Width: 2}, screen.Cell(2, 0))
+	assert.Equal(t, Cell{Grapheme: "🇯🇵", Width: 2}, screen.Cell(5, 0))
+	x, _ = screen.Cursor()
+	assert.Equal(t, 7, x)
+
+	// A grapheme split across writes is joined, and grows wide when it becomes an emoji
+	screen.Reset()
+	screen.Write([]byte("a\u2764"))
+	screen.Write([]byte("\ufe0fb"))
+	assert.Equal(t, Cell{Grapheme: "\u2764\ufe0f", Width: 2}, screen.Cell(1, 0))
+	assert.Equal(t, "b", screen.Cell(3, 0).Grapheme)
+
+	// Overwriting either half of a wide character blanks the other half
+	screen.Reset()
+	screen.WriteString("漢字\x1b[1;2Hx\x1b[1;3Hy")
+	assert.Equal(t, " xy", screen.Line(0))
+
+	// A wide character that doesn't fit on the line wraps whole
+	screen.Reset()
+	screen.WriteString("abcdefg漢")
+	assert.Equal(t, []string{"abcdefg", "漢", ""}, screen.Lines())
+
+	// Erasing or shifting part of a wide character leaves no orphaned half
+	screen.Reset()
+	screen.WriteString("漢字\x1b[1;2H\x1b[1X")
+	assert.Equal(t, "  字", screen.Line(0))
+	screen.WriteString("\x1b[1;4H\x1b[1P") // Delete the right half of 字
+	assert.Equal(t, "", screen.Line(0))
+	assert.Equal(t, Cell{Grapheme: " ", Width: 1}, screen.Cell(2, 0))
+
+	// Text matching counts display columns
+	screen.Reset()
+	screen.WriteString("漢字ab")
+	at, ok := screen.Find("ab")
+	require.True(t, ok)
+	assert.Equal(t, Point{4, 0}, at)
+	assert.Equal(t, "ab", screen.RegionText(Region{Col: 4, Height: 1}))
+	assert.Equal(t, "漢", screen.RegionText(Region{Width: 2, Height: 1}))
+	assert.Equal(t, "漢字ab", screen.HTML())
+}
+
+// TestTruncateANSI_Wide tests truncating lines by display width
+func TestTruncateANSI_Wide(t *testing.T) {
+	assert.Equal(t, "漢", truncateANSI("漢字ab", 3), "a wide character straddling the edge is dropped")
+	assert.Equal(t, "漢字a", truncateANSI("漢字ab", 5))
+	assert.Equal(t, "e\u0301\x1b[31mx\x1b[0m", truncateANSI("e\u0301\x1b[31mxy\x1b[0m", 2))
+}
+
+// TestStageDirector_ScreenFollowsRenderer tests that BubbleTea's renderer draws into the screen
+func TestStageDirector_ScreenFollowsRenderer(t *testing.T) {
+	model := &mockREPLForInteractions{mode: "screen_test"}
+	director := NewStageDirectorWithConfig(t, model, StageConfig{
+		Timeout:      5 * time.Second,
+		TypingSpeed:  0,
+		CaptureViews: false,
+		MaxRetries:   0,
+	})
+	defer director.Stop()
+
+	director.Start().Type("abc")
+
+	screen := director.Screen()
+	require.NotNil(t, screen)
+	assert.Equal(t, "Mock REPL: abc", screen.Line(0))
+
+	director.AssertScreenContains("Mock REPL: abc")
+	assert.False(t, director.HasFailed())
+}
@@ -0,0 +1,208 @@
+package steadicam
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// CellColor is a resolved terminal color.
+// The zero value means "terminal default", which lets renderers substitute
+// their own configured foreground or background.
+type CellColor struct {
+	RGBA color.RGBA // Resolved color value
+	Set  bool       // False when the cell uses the default color
+}
+
+// CellStyle holds the SGR attributes of a single terminal cell
+// The wardrobe department - every glyph dressed exactly as the script demands
+type CellStyle struct {
+	Foreground CellColor
+	Background CellColor
+	Bold       bool
+	Dim        bool
+	Italic     bool
+	Underline  bool
+	Reverse    bool
+}
+
+// ansiPalette holds the 16 standard colors using xterm's default values
+var ansiPalette = [16]color.RGBA{
+	{0, 0, 0, 255},       // black
+	{205, 0, 0, 255},     // red
+	{0, 205, 0, 255},     // green
+	{205, 205, 0, 255},   // yellow
+	{0, 0, 238, 255},     // blue
+	{205, 0, 205, 255},   // magenta
+	{0, 205, 205, 255},   // cyan
+	{229, 229, 229, 255}, // white
+	{127, 127, 127, 255}, // bright black
+	{255, 0, 0, 255},     // bright red
+	{0, 255, 0, 255},     // bright green
+	{255, 255, 0, 255},   // bright yellow
+	{92, 92, 255, 255},   // bright blue
+	{255, 0, 255, 255},   // bright magenta
+	{0, 255, 255, 255},   // bright cyan
+	{255, 255, 255, 255}, // bright white
+}
+
+// ansi256 resolves an xterm 256-color palette index to RGBA
+func ansi256(index int) color.RGBA {
+	switch {
+	case index < 0:
+		return ansiPalette[0]
+	case index < 16:
+		return ansiPalette[index]
+	case index < 232:
+		// 6x6x6 color cube
+		index -= 16
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		return color.RGBA{levels[index/36], levels[(index/6)%6], levels[index%6], 255}
+	case index < 256:
+		// 24-step grayscale ramp
+		gray := uint8(8 + (index-232)*10)
+		return color.RGBA{gray, gray, gray, 255}
+	default:
+		return ansiPalette[15]
+	}
+}
+
+// parseSGRParams splits the parameter string of an SGR sequence into integers.
+// Both ';' and the ITU ':' sub-parameter separator are accepted, and empty
+// parameters default to 0 as terminals do.
+func parseSGRParams(params string) []int {
+	if params == "" {
+		return []int{0}
+	}
+
+	fields := strings.FieldsFunc(params, func(r rune) bool { return r == ';' || r == ':' })
+	if len(fields) == 0 {
+		return []int{0}
+	}
+
+	values := make([]int, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			n = 0
+		}
+		values = append(values, n)
+	}
+	return values
+}
+
+// applySGR updates the style with a sequence of SGR parameters
+func (s *CellStyle) applySGR(params []int) {
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch {
+		case p == 0:
+			*s = CellStyle{}
+		case p == 1:
+			s.Bold = true
+		case p == 2:
+			s.Dim = true
+		case p == 3:
+			s.Italic = true
+		case p == 4:
+			s.Underline = true
+		case p == 7:
+			s.Reverse = true
+		case p == 22:
+			s.Bold = false
+			s.Dim = false
+		case p == 23:
+			s.Italic = false
+		case p == 24:
+			s.Underline = false
+		case p == 27:
+			s.Reverse = false
+		case p >= 30 && p <= 37:
+			s.Foreground = CellColor{RGBA: ansiPalette[p-30], Set: true}
+		case p == 38:
+			var c CellColor
+			c, i = parseExtendedColor(params, i)
+			if c.Set {
+				s.Foreground = c
+			}
+		case p == 39:
+			s.Foreground = CellColor{}
+		case p >= 40 && p <= 47:
+			s.Background = CellColor{RGBA: ansiPalette[p-40], Set: true}
+		case p == 48:
+			var c CellColor
+			c, i = parseExtendedColor(params, i)
+			if c.Set {
+				s.Background = c
+			}
+		case p == 49:
+			s.Background = CellColor{}
+		case p >= 90 && p <= 97:
+			s.Foreground = CellColor{RGBA: ansiPalette[p-90+8], Set: true}
+		case p >= 100 && p <= 107:
+			s.Background = CellColor{RGBA: ansiPalette[p-100+8], Set: true}
+		}
+	}
+}
+
+// parseExtendedColor parses a 38/48 color starting at params[i] and returns
+// the color plus the index of the last parameter consumed
+func parseExtendedColor(params []int, i int) (CellColor, int) {
+	if i+1 >= len(params) {
+		return CellColor{}, i
+	}
+
+	switch params[i+1] {
+	case 5: // 256-color: 38;5;n
+		if i+2 < len(params) {
+			return CellColor{RGBA: ansi256(params[i+2]), Set: true}, i + 2
+		}
+		return CellColor{}, len(params) - 1
+	case 2: // truecolor: 38;2;r;g;b
+		if i+4 < len(params) {
+			return CellColor{
+				RGBA: color.RGBA{clampByte(params[i+2]), clampByte(params[i+3]), clampByte(params[i+4]), 255},
+				Set:  true,
+			}, i + 4
+		}
+		return CellColor{}, len(params) - 1
+	default:
+		return CellColor{}, i + 1
+	}
+}
+
+// clampByte limits an SGR color component to the 0-255 range
+func clampByte(n int) uint8 {
+	if n < 0 {
+		return 0
+	}
+	if n > 255 {
+		return 255
+	}
+	return uint8(n)
+}
+
+// Colors resolves the final glyph and fill colors for the style.
+// Reverse video swaps foreground and background, and dim text is blended
+// halfway towards the background the way most terminals render it.
+func (s CellStyle) Colors(defaultFg, defaultBg color.RGBA) (fg, bg color.RGBA) {
+	fg, bg = defaultFg, defaultBg
+	if s.Foreground.Set {
+		fg = s.Foreground.RGBA
+	}
+	if s.Background.Set {
+		bg = s.Background.RGBA
+	}
+	if s.Reverse {
+		fg, bg = bg, fg
+	}
+	if s.Dim {
+		fg = color.RGBA{
+			uint8((int(fg.R) + int(bg.R)) / 2),
+			uint8((int(fg.G) + int(bg.G)) / 2),
+			uint8((int(fg.B) + int(bg.B)) / 2),
+			fg.A,
+		}
+	}
+	return fg, bg
+}
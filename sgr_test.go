@@ -0,0 +1,141 @@
+package steadicam
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCellStyle_ApplySGR tests SGR parameter parsing into cell attributes
+func TestCellStyle_ApplySGR(t *testing.T) {
+	testCases := []struct {
+		name     string
+		params   string
+		expected CellStyle
+	}{
+		{
+			name:     "16-color foreground and background",
+			params:   "31;42",
+			expected: CellStyle{Foreground: CellColor{ansiPalette[1], true}, Background: CellColor{ansiPalette[2], true}},
+		},
+		{
+			name:     "bright colors",
+			params:   "94;103",
+			expected: CellStyle{Foreground: CellColor{ansiPalette[12], true}, Background: CellColor{ansiPalette[11], true}},
+		},
+		{
+			name:     "256-color cube",
+			params:   "38;5;39",
+			expected: CellStyle{Foreground: CellColor{color.RGBA{0, 175, 255, 255}, true}},
+		},
+		{
+			name:     "256-color grayscale background",
+			params:   "48;5;240",
+			expected: CellStyle{Background: CellColor{color.RGBA{88, 88, 88, 255}, true}},
+		},
+		{
+			name:     "truecolor",
+			params:   "38;2;10;20;30;48;2;40;50;60",
+			expected: CellStyle{Foreground: CellColor{color.RGBA{10, 20, 30, 255}, true}, Background: CellColor{color.RGBA{40, 50, 60, 255}, true}},
+		},
+		{
+			name:     "colon separated truecolor",
+			params:   "38:2:1:2:3",
+			expected: CellStyle{Foreground: CellColor{color.RGBA{1, 2, 3, 255}, true}},
+		},
+		{
+			name:     "text attributes",
+			params:   "1;2;3;4;7",
+			expected: CellStyle{Bold: true, Dim: true, Italic: true, Underline: true, Reverse: true},
+		},
+		{
+			name:     "reset",
+			params:   "1;31;0",
+			expected: CellStyle{},
+		},
+		{
+			name:     "attribute resets",
+			params:   "1;4;7;22;24;27",
+			expected: CellStyle{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			style := CellStyle{}
+			style.applySGR(parseSGRParams(tc.params))
+			assert.Equal(t, tc.expected, style)
+		})
+	}
+}
+
+// TestCellStyle_Colors tests resolution of defaults, reverse video and dim
+func TestCellStyle_Colors(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+
+	fg, bg := CellStyle{}.Colors(white, black)
+	assert.Equal(t, white, fg)
+	assert.Equal(t, black, bg)
+
+	fg, bg = CellStyle{Reverse: true}.Colors(white, black)
+	assert.Equal(t, black, fg)
+	assert.Equal(t, white, bg)
+
+	fg, _ = CellStyle{Dim: true}.Colors(white, black)
+	assert.Equal(t, color.RGBA{127, 127, 127, 255}, fg)
+}
+
+// TestRenderingStage_StyledText tests that rendered cells keep their SGR attributes
+func TestRenderingStage_StyledText(t *testing.T) {
+	rs := NewRenderingStage(Config{
+		Width:      20,
+		Height:     3,
+		Background: color.RGBA{0, 0, 0, 255},
+		Foreground: color.RGBA{255, 255, 255, 255},
+	})
+
+	rs.RenderText("\x1b[1;31mred\x1b[0m plain\n\x1b[48;5;21mblue\nstill blue\x1b[0m")
+
+	screen := rs.Screen()
+	assert.Equal(t, "red plain", screen.Line(0))
+	assert.True(t, screen.Cell(0, 0).Style.Bold)
+	assert.Equal(t, ansiPalette[1], screen.Cell(2, 0).Style.Foreground.RGBA)
+	assert.Equal(t, CellStyle{}, screen.Cell(4, 0).Style)
+
+	// SGR state carries across lines like a real terminal
+	assert.Equal(t, ansi256(21), screen.Cell(0, 1).Style.Background.RGBA)
+	assert.Equal(t, ansi256(21), screen.Cell(0, 2).Style.Background.RGBA)
+
+	// Background fills and glyph colors are painted into the frame
+	img := rs.renderImage()
+	assert.Equal(t, ansi256(21), img.RGBAAt(1, rs.charHeight+1))
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(1, 1))
+
+	var hasRed bool
+	for y := 0; y < rs.charHeight; y++ {
+		for x := 0; x < rs.charWidth; x++ {
+			if img.RGBAAt(x, y) == ansiPalette[1] {
+				hasRed = true
+			}
+		}
+	}
+	assert.True(t, hasRed, "first glyph should be drawn in red")
+}
+
+// TestRenderingStage_WideCells tests that wide characters paint both of their cells
+func TestRenderingStage_WideCells(t *testing.T) {
+	rs := NewRenderingStage(Config{
+		Width:      4,
+		Height:     1,
+		Background: color.RGBA{0, 0, 0, 255},
+		Foreground: color.RGBA{255, 255, 255, 255},
+	})
+	rs.RenderText("\x1b[44m漢\x1b[0mx")
+
+	img := rs.renderImage()
+	assert.Equal(t, ansiPalette[4], img.RGBAAt(rs.charWidth+1, 1), "the spacer cell shares the background")
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(3*rs.charWidth+1, 1))
+	assert.Equal(t, "漢x", rs.Screen().Line(0))
+}
@@ -3,6 +3,7 @@ package steadicam
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -37,10 +38,15 @@ func (d *StageDirector) syncModelUpdates() {
 			// Update model state safely with read lock
 			d.modelMu.Lock()
 			d.latestModel = update.model
+			d.lastUpdateAt = update.timestamp
+			d.markMessageObserved(update.messageID)
 			atomic.StoreInt64(&d.lastProcessedSeq, update.sequence)
 			atomic.AddInt64(&d.updatesProcessed, 1)
 			d.modelMu.Unlock()
 
+			// Wake every wait that is watching for a new model
+			d.notifyStateChange()
+
 		case <-d.ctx.Done():
 			return
 		}
@@ -84,22 +90,41 @@ func (d *StageDirector) Start() *StageDirector {
 		return d
 	}
 
-	d.t.Logf("[TRACE] Start: Creating headless bubbletea program...")
+	d.tracef("Start: Creating headless bubbletea program...")
+
+	// Start model synchronization now that the context is final - WithTimeout
+	// replaces it before Start, which would otherwise strand the goroutine
+	go d.syncModelUpdates()
+
+	// Models see their terminal size after Init and before their first view
+	initCmd, initialized := d.initWithSize()
 
 	// Wrap the model to capture state changes
 	wrappedModel := stageModelWrapper{
-		REPLModel: d.model,
-		director:  d,
+		REPLModel:   d.model,
+		director:    d,
+		initCmd:     initCmd,
+		initialized: initialized,
 	}
 
-	// Create a headless program (no terminal output) with test-friendly options
+	// Anything rendered from here on is newer than the starting model
+	d.modelMu.Lock()
+	d.lastUpdateAt = time.Now()
+	d.modelMu.Unlock()
+
+	// Create a headless program whose real renderer draws into the virtual terminal,
+	// keeping a copy of the output for casts unless the stage is quiet
+	var output io.Writer = d.screen
+	if !d.config.Quiet {
+		d.output = newOutputLog(d.screen)
+		output = d.output
+	}
 	d.program = tea.NewProgram(wrappedModel,
-		tea.WithoutRenderer(),    // No terminal rendering
-		tea.WithInput(nil),       // No input reader (prevents TTY access)
-		tea.WithOutput(nil),      // No output writer
+		tea.WithInput(nil),     // No input reader (prevents TTY access)
+		tea.WithOutput(output), // Renderer output feeds the screen model
 	)
 
-	d.t.Logf("[TRACE] Start: Starting program in background goroutine...")
+	d.tracef("Start: Starting program in background goroutine...")
 
 	// Run the program in a separate goroutine
 	go func() {
@@ -109,14 +134,14 @@ func (d *StageDirector) Start() *StageDirector {
 			}
 		}()
 
-		d.t.Logf("[TRACE] Start: About to call program.Run()...")
+		d.tracef("Start: About to call program.Run()...")
 		_, err := d.program.Run()
 		if err != nil {
-			d.t.Logf("[TRACE] Start: program.Run() returned with error=%v", err)
+			d.tracef("Start: program.Run() returned with error=%v", err)
 		}
 	}()
 
-	d.t.Logf("[TRACE] Start: Waiting for program to be ready...")
+	d.tracef("Start: Waiting for program to be ready...")
 	if err := d.waitForProgramReady(); err != nil {
 		d.recordTrip(newStageTrip("STARTUP_FAILED", err.Error(), map[string]interface{}{
 			"error": err.Error(),
@@ -124,9 +149,12 @@ func (d *StageDirector) Start() *StageDirector {
 		return d
 	}
 
-	d.t.Logf("[TRACE] Start: Program ready, capturing initial snapshot...")
+	d.tracef("Start: Program ready, capturing initial snapshot...")
 	d.started = true
 
+	// Size the renderer too; the model already has its size
+	d.sizeRenderer()
+
 	// Capture initial state with panic protection
 	if d.config.CaptureViews {
 		// Safely capture initial view
@@ -170,7 +198,7 @@ func (d *StageDirector) Start() *StageDirector {
 		})
 	}
 
-	d.t.Logf("[TRACE] Start: Start completed successfully")
+	d.tracef("Start: Start completed successfully")
 	return d
 }
 
@@ -178,6 +206,14 @@ func (d *StageDirector) Start() *StageDirector {
 func (d *StageDirector) Stop() *StageResult {
 	startTime := time.Now()
 
+	// Let the renderer draw the last frame, so the screen and cast end where the stage did
+	if d.started {
+		d.waitForRender()
+	}
+	if d.clock != nil {
+		d.reportWallClockTimers()
+	}
+
 	// Ensure we capture final state before stopping with panic protection
 	if d.config.CaptureViews && d.started {
 		// Safely capture final view
@@ -267,8 +303,9 @@ func (d *StageDirector) Stop() *StageResult {
 		}
 	}
 
-	return &StageResult{
+	result := &StageResult{
 		Actions:      d.interactions,
+		Commands:     d.IssuedCommands(),
 		Snapshots:    d.snapshots,
 		Success:      success,
 		Duration:     duration,
@@ -277,6 +314,13 @@ func (d *StageDirector) Stop() *StageResult {
 		ErrorDetails: errorDetails.String(),
 		TripReport:   tripReport,
 	}
+	result.Width, result.Height = d.config.terminalSize()
+	if d.output != nil {
+		result.StartedAt = d.output.start
+		result.Width, result.Height = d.output.width, d.output.height
+		result.Output = d.output.output()
+	}
+	return result
 }
 
 // WaitForMode waits for the application to enter a specific mode
@@ -284,28 +328,21 @@ func (d *StageDirector) WaitForMode(expectedMode string) *StageDirector {
 	if d.failed {
 		return d
 	}
+	if !d.requireInspection("mode", modelCapabilities.reportsMode) {
+		return d
+	}
 
-	timeout := time.NewTimer(d.config.Timeout)
-	defer timeout.Stop()
-
-	for {
-		select {
-		case <-timeout.C:
-			trip := newStageTrip("WAIT_MODE_TIMEOUT", fmt.Sprintf("Timeout waiting for mode '%s'", expectedMode), map[string]interface{}{
-				"expected_mode": expectedMode,
-				"current_mode":  d.getCurrentMode(),
-			})
-			d.recordTrip(trip)
-			return d
-		case <-d.ctx.Done():
-			return d
-		default:
-			if d.getCurrentMode() == expectedMode {
-				return d
-			}
-			time.Sleep(10 * time.Millisecond)
-		}
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		return d.getCurrentMode() == expectedMode
+	})
+	if err == errWaitTimeout {
+		trip := newStageTrip("WAIT_MODE_TIMEOUT", fmt.Sprintf("Timeout waiting for mode '%s'", expectedMode), map[string]interface{}{
+			"expected_mode": expectedMode,
+			"current_mode":  d.getCurrentMode(),
+		})
+		d.recordTrip(trip)
 	}
+	return d
 }
 
 // WaitForSearchResults waits for search results to appear and stabilize
@@ -314,28 +351,20 @@ func (d *StageDirector) WaitForSearchResults() *StageDirector {
 		return d
 	}
 
-	timeout := time.NewTimer(d.config.Timeout)
-	defer timeout.Stop()
-
-	for {
-		select {
-		case <-timeout.C:
-			trip := newStageTrip("WAIT_RESULTS_TIMEOUT", "Timeout waiting for search results", map[string]interface{}{
-				"current_view": d.truncateString(d.getCurrentView(), 200),
-			})
-			d.recordTrip(trip)
-			return d
-		case <-d.ctx.Done():
-			return d
-		default:
-			view := d.getCurrentView()
-			if strings.Contains(view, "Live Results:") || strings.Contains(view, "Found") {
-				time.Sleep(50 * time.Millisecond) // Brief stabilization
-				return d
-			}
-			time.Sleep(10 * time.Millisecond)
-		}
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		view := d.getCurrentView()
+		return strings.Contains(view, "Live Results:") || strings.Contains(view, "Found")
+	})
+	switch err {
+	case nil:
+		time.Sleep(50 * time.Millisecond) // Brief stabilization
+	case errWaitTimeout:
+		trip := newStageTrip("WAIT_RESULTS_TIMEOUT", "Timeout waiting for search results", map[string]interface{}{
+			"current_view": d.truncateString(d.getCurrentView(), 200),
+		})
+		d.recordTrip(trip)
 	}
+	return d
 }
 
 // WaitForText waits for specific text to appear in the current view
@@ -344,54 +373,105 @@ func (d *StageDirector) WaitForText(text string) *StageDirector {
 		return d
 	}
 
-	timeout := time.NewTimer(d.config.Timeout)
-	defer timeout.Stop()
+	err := d.waitUntil(d.config.Timeout, func() bool {
+		return strings.Contains(d.getCurrentView(), text)
+	})
+	if err == errWaitTimeout {
+		trip := newStageTrip("WAIT_TEXT_TIMEOUT", fmt.Sprintf("Timeout waiting for text '%s'", text), map[string]interface{}{
+			"expected_text": text,
+			"current_view":  d.getCurrentView(),
+		})
+		d.recordTrip(trip)
+	}
+	return d
+}
 
-	for {
-		select {
-		case <-timeout.C:
-			trip := newStageTrip("WAIT_TEXT_TIMEOUT", fmt.Sprintf("Timeout waiting for text '%s'", text), map[string]interface{}{
-				"expected_text": text,
-				"current_view":  d.getCurrentView(),
-			})
-			d.recordTrip(trip)
-			return d
-		case <-d.ctx.Done():
-			return d
-		default:
-			if strings.Contains(d.getCurrentView(), text) {
-				return d
-			}
-			time.Sleep(10 * time.Millisecond)
-		}
+// WaitFor waits until the predicate holds for the current model.
+//
+// The name identifies the predicate in recorded actions and trip reports.
+// The predicate is evaluated against every new model, so it should be cheap
+// and free of side effects.
+//
+// Example:
+//
+//	director.WaitFor("three results", func(m steadicam.REPLModel) bool {
+//		return len(m.(*SearchREPL).results) == 3
+//	})
+func (d *StageDirector) WaitFor(name string, predicate func(REPLModel) bool) *StageDirector {
+	return d.waitFor(name, d.config.Timeout, predicate, "WAIT_FOR_TIMEOUT")
+}
+
+// WaitForWithin is WaitFor with its own timeout instead of the configured one
+func (d *StageDirector) WaitForWithin(name string, timeout time.Duration, predicate func(REPLModel) bool) *StageDirector {
+	return d.waitFor(name, timeout, predicate, "WAIT_FOR_TIMEOUT")
+}
+
+// WaitForCondition waits until the model's CheckCondition reports the named condition
+func (d *StageDirector) WaitForCondition(condition string) *StageDirector {
+	if d.failed {
+		return d
+	}
+	if !d.requireInspection("conditions", modelCapabilities.checksConditions) {
+		return d
 	}
+	return d.waitFor("condition="+condition, d.config.Timeout, func(m REPLModel) bool {
+		return m.CheckCondition(condition)
+	}, "WAIT_CONDITION_TIMEOUT")
 }
 
-// waitForProgramReady waits for the BubbleTea program to be ready
-func (d *StageDirector) waitForProgramReady() error {
-	d.t.Logf("[TRACE] waitForProgramReady: Starting wait with timeout=%v", d.config.Timeout)
+// waitFor evaluates a named predicate on each new model and records the outcome
+func (d *StageDirector) waitFor(name string, timeout time.Duration, predicate func(REPLModel) bool, tripType string) *StageDirector {
+	if d.failed {
+		return d
+	}
+
+	var lastView string
+	err := d.waitUntil(timeout, func() bool {
+		model := d.currentModel()
+		lastView = model.View()
+		return predicate(model)
+	})
+
+	switch err {
+	case nil:
+		d.recordStageAction("wait", "for="+name)
+	case errWaitTimeout:
+		trip := newStageTrip(tripType, fmt.Sprintf("Timeout after %v waiting for '%s'", timeout, name), map[string]interface{}{
+			"predicate":    name,
+			"timeout":      timeout,
+			"current_view": lastView,
+		})
+		d.recordTrip(trip)
+	}
+	return d
+}
 
-	timeout := time.NewTimer(d.config.Timeout)
-	defer timeout.Stop()
+// programReadyTimeout caps how long Start waits for the first non-empty view
+const programReadyTimeout = time.Second
 
-	for i := 0; i < 50; i++ { // Try up to 50 times
-		select {
-		case <-timeout.C:
-			return fmt.Errorf("timeout waiting for program to be ready")
-		case <-d.ctx.Done():
-			return fmt.Errorf("context cancelled while waiting for program")
-		default:
-			// Check if we have a non-empty view (indicates program is ready)
-			if view := d.getCurrentView(); len(view) > 0 {
-				d.t.Logf("[TRACE] waitForProgramReady: SUCCESS after %d checks - view length=%d", i+1, len(view))
-				return nil
-			}
-			time.Sleep(20 * time.Millisecond)
-		}
+// waitForProgramReady waits for the BubbleTea program to be ready
+func (d *StageDirector) waitForProgramReady() error {
+	timeout := d.config.Timeout
+	if timeout > programReadyTimeout {
+		timeout = programReadyTimeout
 	}
+	d.tracef("waitForProgramReady: Starting wait with timeout=%v", timeout)
 
-	d.t.Logf("[TRACE] waitForProgramReady: TIMEOUT after 50 checks")
-	return fmt.Errorf("program never became ready")
+	start := time.Now()
+	// A non-empty view indicates the program is ready
+	err := d.waitUntil(timeout, func() bool {
+		return len(d.getCurrentView()) > 0
+	})
+	switch err {
+	case nil:
+		d.tracef("waitForProgramReady: SUCCESS after %v - view length=%d", time.Since(start), len(d.getCurrentView()))
+		return nil
+	case errWaitTimeout:
+		d.tracef("waitForProgramReady: TIMEOUT after %v", time.Since(start))
+		return fmt.Errorf("timeout waiting for program to be ready")
+	default:
+		return fmt.Errorf("context cancelled while waiting for program")
+	}
 }
 
 // waitForViewChange waits for the view to change from a previous state
@@ -401,34 +481,67 @@ func (d *StageDirector) waitForViewChange(previousView string) {
 		timeout = time.Second // Cap at 1 second for view changes
 	}
 
-	d.t.Logf("[TRACE] waitForViewChange: Starting wait, timeout=%v, prev_view_len=%d", timeout, len(previousView))
+	d.tracef("waitForViewChange: Starting wait, timeout=%v, prev_view_len=%d", timeout, len(previousView))
 
 	start := time.Now()
-	timer := time.NewTimer(timeout)
-	defer timer.Stop()
+	var currentView string
+	err := d.waitUntil(timeout, func() bool {
+		currentView = d.getCurrentView()
+		return currentView != previousView
+	})
+	switch err {
+	case nil:
+		d.tracef("waitForViewChange: SUCCESS after %v, new_view_len=%d", time.Since(start), len(currentView))
+	case errWaitTimeout:
+		d.tracef("waitForViewChange: TIMEOUT after %v", time.Since(start))
+	default:
+		d.tracef("waitForViewChange: Context cancelled")
+	}
+}
 
-	checkCount := 0
-	for {
-		select {
-		case <-timer.C:
-			d.t.Logf("[TRACE] waitForViewChange: TIMEOUT after %v (%d checks)", time.Since(start), checkCount)
+// renderSettleTimeout bounds how long to wait for the renderer to flush a frame
+const renderSettleTimeout = 100 * time.Millisecond
+
+// waitForRender waits until BubbleTea's renderer has flushed the latest model
+// to the virtual terminal. The renderer skips frames identical to the previous
+// one, so the wait gives up after a few frame intervals.
+func (d *StageDirector) waitForRender() {
+	if d.program == nil {
+		return
+	}
+
+	d.modelMu.RLock()
+	updatedAt := d.lastUpdateAt
+	d.modelMu.RUnlock()
+
+	deadline := time.Now().Add(renderSettleTimeout)
+	for time.Now().Before(deadline) {
+		if d.screen.LastDraw().After(updatedAt) {
 			return
+		}
+		select {
 		case <-d.ctx.Done():
-			d.t.Logf("[TRACE] waitForViewChange: Context cancelled")
 			return
-		default:
-			checkCount++
-			currentView := d.getCurrentView()
-			if currentView != previousView {
-				elapsed := time.Since(start)
-				d.t.Logf("[TRACE] waitForViewChange: SUCCESS after %v (%d checks), new_view_len=%d", elapsed, checkCount, len(currentView))
-				return
-			}
-			time.Sleep(2 * time.Millisecond) // Short sleep between checks
+		case <-time.After(2 * time.Millisecond):
 		}
 	}
 }
 
+// Screen returns the virtual terminal that BubbleTea's renderer draws into.
+// Call it after an interaction to inspect cursor position, alternate screen
+// state, or styled cells exactly as a user's terminal would show them.
+func (d *StageDirector) Screen() *Screen {
+	d.waitForRender()
+	return d.screen
+}
+
+// currentModel safely retrieves the latest model
+func (d *StageDirector) currentModel() REPLModel {
+	d.modelMu.RLock()
+	defer d.modelMu.RUnlock()
+	return d.latestModel
+}
+
 // getCurrentView safely retrieves the current view content
 func (d *StageDirector) getCurrentView() string {
 	d.modelMu.RLock()
@@ -472,6 +585,34 @@ func (d *StageDirector) getCurrentMode() string {
 	return ""
 }
 
+// Model returns the latest model produced by the running program.
+// Models staged through NewStageDirectorForModel are returned unwrapped, so
+// tests can type-assert back to their concrete type.
+func (d *StageDirector) Model() tea.Model {
+	return UnwrapModel(d.currentModel())
+}
+
+// requireInspection records a trip when the model cannot report the requested detail.
+// REPLModel implementations always pass; adapted tea.Models pass only when they
+// implement the matching optional interface or were given an inspector.
+func (d *StageDirector) requireInspection(detail string, supported func(modelCapabilities) bool) bool {
+	d.modelMu.RLock()
+	model := d.latestModel
+	d.modelMu.RUnlock()
+
+	adapter, ok := model.(modelAdapter)
+	if !ok || supported(adapter) {
+		return true
+	}
+
+	trip := newStageTrip("UNSUPPORTED_INSPECTION", fmt.Sprintf("Model %T does not report its %s", adapter.Model, detail), map[string]interface{}{
+		"detail":     detail,
+		"model_type": fmt.Sprintf("%T", adapter.Model),
+	})
+	d.recordTrip(trip)
+	return false
+}
+
 // GetLatestSnapshot returns the most recent view snapshot
 func (d *StageDirector) GetLatestSnapshot() StageSnapshot {
 	if len(d.snapshots) == 0 {
@@ -485,14 +626,6 @@ func (d *StageDirector) GetStageActionCount() int {
 	return len(d.interactions)
 }
 
-// notifyStateChange handles state transition notifications
-func (d *StageDirector) notifyStateChange(prevModel, newModel REPLModel) {
-	// State change notification - currently used for debugging
-	// Currently disabled to prevent race conditions in tests
-	// The polling approach in waitForViewChange handles state detection reliably
-	_ = prevModel
-	_ = newModel
-}
 
 // getErrorMessage returns a human-readable error message
 func (d *StageDirector) getErrorMessage() string {
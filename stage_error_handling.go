@@ -8,6 +8,19 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// Init records the model's initial command like any other. Stages with an
+// initial size have already run Init, so its commands are handed over instead.
+func (w stageModelWrapper) Init() tea.Cmd {
+	if w.initialized {
+		return w.initCmd
+	}
+	cmd := w.REPLModel.Init()
+	if w.director != nil {
+		cmd = w.director.interceptCommand(cmd, nil)
+	}
+	return cmd
+}
+
 // Update intercepts model updates to keep stage director in sync
 // Stanley's steadicam operator - smooth, continuous state capture
 func (w stageModelWrapper) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -22,7 +35,18 @@ func (w stageModelWrapper) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	}()
 
+	// The initial size was applied to the model before Start; only the renderer needs it
+	if _, ok := msg.(tea.WindowSizeMsg); ok && w.director != nil && w.director.takeRendererOnlySize() {
+		return w, nil
+	}
+
+	// Unwrap director-staged messages so the model only ever sees the original
+	msg, messageID := unwrapStagedMsg(msg)
+
 	newModel, cmd := w.REPLModel.Update(msg)
+	if w.director != nil {
+		cmd = w.director.interceptCommand(cmd, msg)
+	}
 
 	// Validate model state for fail-fast detection
 	if newModel == nil {
@@ -37,21 +61,36 @@ func (w stageModelWrapper) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if replModel, ok := newModel.(REPLModel); ok {
 			// Generate sequence number atomically
 			seq := atomic.AddInt64(&w.director.updateSeq, 1)
+			if _, ok := msg.(tea.WindowSizeMsg); ok {
+				atomic.StoreInt64(&w.director.sizeSeq, seq)
+			}
 
 			update := modelUpdate{
 				model:     replModel,
 				sequence:  seq,
 				timestamp: time.Now(),
+				messageID: messageID,
 			}
 
-			// Non-blocking send with buffer overflow protection
-			select {
-			case w.director.modelChan <- update:
-				atomic.AddInt64(&w.director.updatesSent, 1)
-			default:
-				// Buffer full - handle overflow gracefully
-				atomic.AddInt64(&w.director.bufferOverflows, 1)
-				atomic.AddInt64(&w.director.droppedUpdates, 1)
+			if w.director.config.LosslessSync {
+				// Back-pressure: hold the event loop until the director takes the update
+				select {
+				case w.director.modelChan <- update:
+					atomic.AddInt64(&w.director.updatesSent, 1)
+				case <-w.director.ctx.Done():
+				}
+			} else {
+				// Non-blocking send with buffer overflow protection
+				select {
+				case w.director.modelChan <- update:
+					atomic.AddInt64(&w.director.updatesSent, 1)
+				default:
+					// Buffer full - handle overflow gracefully
+					atomic.AddInt64(&w.director.bufferOverflows, 1)
+					atomic.AddInt64(&w.director.droppedUpdates, 1)
+					// Models mutated in place still changed, so wake any waits
+					w.director.notifyStateChange()
+				}
 			}
 		}
 	}
@@ -83,6 +122,7 @@ func (d *StageDirector) GetSynchronizationStats() map[string]int64 {
 		"sequence_gaps":       atomic.LoadInt64(&d.sequenceGaps),
 		"duplicate_updates":   atomic.LoadInt64(&d.duplicateUpdates),
 		"updates_dropped":     atomic.LoadInt64(&d.droppedUpdates),
+		"messages_staged":     atomic.LoadInt64(&d.nextMessageID),
 		"buffer_length":       int64(len(d.modelChan)),
 		"buffer_capacity":     int64(cap(d.modelChan)),
 	}
@@ -107,6 +147,7 @@ func (d *StageDirector) GetBufferUtilization() float64 {
 func (d *StageDirector) ResetMetrics() {
 	atomic.StoreInt64(&d.updateSeq, 0)
 	atomic.StoreInt64(&d.lastProcessedSeq, 0)
+	atomic.StoreInt64(&d.sizeSeq, 0)
 	atomic.StoreInt64(&d.droppedUpdates, 0)
 	atomic.StoreInt64(&d.updatesSent, 0)
 	atomic.StoreInt64(&d.updatesProcessed, 0)
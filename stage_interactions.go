@@ -1,6 +1,7 @@
 package steadicam
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -117,6 +118,41 @@ func (d *StageDirector) ClearInput() *StageDirector {
 	return d
 }
 
+// Paste simulates pasting text into a terminal with bracketed paste enabled.
+//
+// The whole payload arrives as one KeyMsg with Paste set, exactly as BubbleTea
+// reports a bracketed paste, so large inputs take a single round trip instead
+// of one per character. Newlines and tabs are delivered as runes, not as
+// Enter or Tab presses.
+//
+// Example:
+//
+//	director.Paste(largeQuery).PressEnter()
+func (d *StageDirector) Paste(text string) *StageDirector {
+	if text == "" {
+		return d
+	}
+	d.sendMessage(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true})
+	d.recordStageAction("paste", d.truncateString(text, 50))
+	return d
+}
+
+// Focus simulates the terminal window gaining focus by sending tea.FocusMsg.
+// Real terminals only report focus to programs using tea.WithReportFocus;
+// the stage delivers it regardless.
+func (d *StageDirector) Focus() *StageDirector {
+	d.sendMessage(tea.FocusMsg{})
+	d.recordStageAction("focus", "gained")
+	return d
+}
+
+// Blur simulates the terminal window losing focus by sending tea.BlurMsg
+func (d *StageDirector) Blur() *StageDirector {
+	d.sendMessage(tea.BlurMsg{})
+	d.recordStageAction("focus", "lost")
+	return d
+}
+
 // Wait pauses stage execution for the specified duration.
 //
 // Use this method when you need to wait for a specific amount of time,
@@ -129,7 +165,11 @@ func (d *StageDirector) ClearInput() *StageDirector {
 //
 //	director.Type("hello").Wait(100*time.Millisecond).PressEnter()
 func (d *StageDirector) Wait(duration time.Duration) *StageDirector {
-	time.Sleep(duration)
+	if d.clock != nil {
+		d.advanceClock(duration) // Virtual time passes instantly
+	} else {
+		time.Sleep(duration)
+	}
 	d.recordStageAction("wait", duration)
 	d.captureSnapshot("wait")
 	return d
@@ -147,8 +187,27 @@ func (d *StageDirector) AssertViewContains(text string) *StageDirector {
 	return d
 }
 
+// AssertScreenContains verifies that the virtual terminal shows the specified text.
+//
+// Unlike AssertViewContains, which inspects the raw View() string, this checks
+// what BubbleTea's renderer actually drew: escape sequences are interpreted,
+// long lines are wrapped or truncated, and alternate screen content is honored.
+func (d *StageDirector) AssertScreenContains(text string) *StageDirector {
+	screen := d.Screen().String()
+	if !strings.Contains(screen, text) {
+		trip := newStageTrip("assertion", "Screen does not contain expected text: "+text, map[string]interface{}{"expected": text, "actual_screen": screen})
+		d.recordTrip(trip)
+		return d
+	}
+	d.recordStageAction("assertion", "screen_contains="+text)
+	return d
+}
+
 // AssertMode verifies that the REPL is in the expected mode
 func (d *StageDirector) AssertMode(expectedMode string) *StageDirector {
+	if !d.requireInspection("mode", modelCapabilities.reportsMode) {
+		return d
+	}
 	actualMode := d.getCurrentMode()
 	if actualMode != expectedMode {
 		trip := newStageTrip("assertion", "Expected mode "+expectedMode+", got "+actualMode, map[string]interface{}{"expected": expectedMode, "actual": actualMode})
@@ -161,6 +220,9 @@ func (d *StageDirector) AssertMode(expectedMode string) *StageDirector {
 
 // AssertInputEquals verifies that the current input matches the expected value
 func (d *StageDirector) AssertInputEquals(expected string) *StageDirector {
+	if !d.requireInspection("input", modelCapabilities.reportsInput) {
+		return d
+	}
 	actual := d.getCurrentInput()
 	if actual != expected {
 		trip := newStageTrip("assertion", "Expected input '"+expected+"', got '"+actual+"'", map[string]interface{}{"expected": expected, "actual": actual})
@@ -171,8 +233,39 @@ func (d *StageDirector) AssertInputEquals(expected string) *StageDirector {
 	return d
 }
 
+// AssertThat verifies a named check against the current model.
+//
+// The check returns nil when the model is as expected, or an error describing
+// what is wrong; the error message becomes part of the trip report.
+//
+// Example:
+//
+//	director.AssertThat("cursor on first item", func(m steadicam.REPLModel) error {
+//		if c := m.(*ListREPL).cursor; c != 0 {
+//			return fmt.Errorf("cursor at %d", c)
+//		}
+//		return nil
+//	})
+func (d *StageDirector) AssertThat(name string, check func(REPLModel) error) *StageDirector {
+	model := d.currentModel()
+	if err := check(model); err != nil {
+		trip := newStageTrip("assertion", fmt.Sprintf("Assertion '%s' failed: %v", name, err), map[string]interface{}{
+			"predicate":   name,
+			"error":       err.Error(),
+			"actual_view": model.View(),
+		})
+		d.recordTrip(trip)
+		return d
+	}
+	d.recordStageAction("assertion", "that="+name)
+	return d
+}
+
 // AssertNoSearchResults verifies that no search results are currently displayed
 func (d *StageDirector) AssertNoSearchResults() *StageDirector {
+	if !d.requireInspection("conditions", modelCapabilities.checksConditions) {
+		return d
+	}
 	if d.latestModel.CheckCondition("search_results") {
 		trip := newStageTrip("assertion", "Expected no search results, but found some", nil)
 		d.recordTrip(trip)
@@ -186,47 +279,76 @@ func (d *StageDirector) AssertNoSearchResults() *StageDirector {
 func (d *StageDirector) sendMessage(msg tea.Msg) {
 	if d.program != nil {
 		currentView := d.getCurrentView()
-		d.t.Logf("[TRACE] sendMessage: About to send message type=%T", msg)
-		d.t.Logf("[TRACE] sendMessage: Current view length=%d, first_50_chars=%q",
+		d.tracef("sendMessage: About to send message type=%T", msg)
+		d.tracef("sendMessage: Current view length=%d, first_50_chars=%q",
 			len(currentView), d.truncateString(currentView, 50))
 
 		sendStart := time.Now()
-		d.program.Send(msg)
-		d.t.Logf("[TRACE] sendMessage: Message sent in %v, waiting for view change...", time.Since(sendStart))
+		if d.config.LosslessSync && shouldStage(msg) {
+			// Wait for the model produced by this exact message
+			d.sendStagedMessage(msg)
+			d.tracef("sendMessage: Message observed in %v", time.Since(sendStart))
+		} else {
+			d.program.Send(msg)
+			d.tracef("sendMessage: Message sent in %v, waiting for view change...", time.Since(sendStart))
 
-		// Wait for the UI to update by checking for view changes
-		d.waitForViewChange(currentView)
+			// Wait for the UI to update by checking for view changes
+			d.waitForViewChange(currentView)
+		}
 		d.captureSnapshot("interaction")
 
 		finalView := d.getCurrentView()
-		d.t.Logf("[TRACE] sendMessage: Complete. Final view length=%d, changed=%t",
+		d.tracef("sendMessage: Complete. Final view length=%d, changed=%t",
 			len(finalView), finalView != currentView)
 	}
 }
 
+// tracef logs a [TRACE] line unless the director is running quietly
+func (d *StageDirector) tracef(format string, args ...interface{}) {
+	if d.config.Quiet || d.t == nil {
+		return
+	}
+	d.t.Helper()
+	d.t.Logf("[TRACE] "+format, args...)
+}
+
 // recordStageAction logs an interaction step
 func (d *StageDirector) recordStageAction(actionType string, details interface{}) {
+	d.recordStageResult(actionType, details, nil)
+}
+
+// recordStageResult logs an interaction step along with what it produced
+func (d *StageDirector) recordStageResult(actionType string, details, result interface{}) {
 	d.interactions = append(d.interactions, StageAction{
 		Timestamp: time.Now(),
 		Type:      actionType,
 		Details:   details,
+		Result:    result,
 	})
+
+	// Report wall-clock timers the action led to while its line is on the stack
+	if d.clock != nil {
+		d.reportWallClockTimers()
+	}
 }
 
 // captureSnapshot captures the current state of the REPL
 func (d *StageDirector) captureSnapshot(reason string) {
-	if !d.config.CaptureViews {
+	if !d.config.CaptureViews || d.config.Quiet {
 		return
 	}
 
-	snapshot := StageSnapshot{
+	d.snapshots = append(d.snapshots, d.takeSnapshot())
+}
+
+// takeSnapshot reads the current view, mode and input without recording them
+func (d *StageDirector) takeSnapshot() StageSnapshot {
+	return StageSnapshot{
 		Timestamp: time.Now(),
 		View:      d.getCurrentView(),
 		Mode:      d.getCurrentMode(),
 		Input:     d.getCurrentInput(),
 	}
-
-	d.snapshots = append(d.snapshots, snapshot)
 }
 
 // recordTrip records a trip using the trip handler and marks stage as failed if needed
@@ -1,11 +1,14 @@
 package steadicam
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // mockREPLForInteractions implements REPLModel for testing stage interactions
@@ -221,6 +224,90 @@ func TestStageDirector_Wait(t *testing.T) {
 	assert.True(t, hasWaitAction)
 }
 
+// TestStageDirector_WaitForAndAssertThat tests custom predicates and their trip reports
+func TestStageDirector_WaitForAndAssertThat(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, burstModel{}, StageConfig{
+		Timeout:      time.Second,
+		TypingSpeed:  0,
+		CaptureViews: false,
+		MaxRetries:   0,
+	})
+	defer director.Stop()
+
+	keys := func(m REPLModel) int { return UnwrapModel(m).(burstModel).keys }
+
+	director.Start().
+		Type("kk").
+		WaitFor("two keys", func(m REPLModel) bool { return keys(m) == 2 }).
+		AssertThat("no ticks yet", func(m REPLModel) error {
+			if ticks := UnwrapModel(m).(burstModel).ticks; ticks != 0 {
+				return fmt.Errorf("got %d ticks", ticks)
+			}
+			return nil
+		})
+	assert.False(t, director.HasFailed())
+
+	var details []interface{}
+	for _, action := range director.interactions {
+		if action.Type == "wait" || action.Type == "assertion" {
+			details = append(details, action.Details)
+		}
+	}
+	assert.Equal(t, []interface{}{"for=two keys", "that=no ticks yet"}, details)
+
+	// A failing check reports its name, error and the view it saw
+	director.AssertThat("five keys", func(m REPLModel) error {
+		return fmt.Errorf("have %d", keys(m))
+	})
+	require.NotNil(t, director.lastTrip)
+	assert.Equal(t, "five keys", director.lastTrip.Context["predicate"])
+	assert.Equal(t, "have 2", director.lastTrip.Context["error"])
+	assert.Equal(t, "keys=2 ticks=0", director.lastTrip.Context["actual_view"])
+
+	// Per-call timeouts override the configured one
+	start := time.Now()
+	director.failed = false
+	director.WaitForWithin("never", 20*time.Millisecond, func(REPLModel) bool { return false })
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, "WAIT_FOR_TIMEOUT", director.lastTrip.Type)
+	assert.Equal(t, "never", director.lastTrip.Context["predicate"])
+	assert.Equal(t, "keys=2 ticks=0", director.lastTrip.Context["current_view"])
+}
+
+// focusLogModel logs pastes and focus changes
+type focusLogModel struct {
+	events []string
+}
+
+func (m focusLogModel) Init() tea.Cmd { return nil }
+func (m focusLogModel) View() string  { return "events: " + strings.Join(m.events, "|") }
+
+func (m focusLogModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.events = append(m.events, fmt.Sprintf("key paste=%t runes=%d", msg.Paste, len(msg.Runes)))
+	case tea.FocusMsg:
+		m.events = append(m.events, "focus")
+	case tea.BlurMsg:
+		m.events = append(m.events, "blur")
+	}
+	return m, nil
+}
+
+// TestStageDirector_PasteAndFocus tests bracketed paste and focus reporting
+func TestStageDirector_PasteAndFocus(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, focusLogModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	payload := strings.Repeat("SELECT * FROM items;\n", 100)
+	start := time.Now()
+	director.Start().Paste(payload).Blur().Focus()
+	assert.Less(t, time.Since(start), time.Second, "a paste is one message, not one per rune")
+
+	director.WaitForText("key paste=true runes=2100|blur|focus")
+	assert.False(t, director.HasFailed(), director.getErrorMessage())
+}
+
 // TestStageDirector_Snapshots tests view capture functionality
 func TestStageDirector_Snapshots(t *testing.T) {
 	model := &mockREPLForInteractions{mode: "snapshot_test"}
@@ -424,7 +511,7 @@ func TestStageDirector_ErrorHandling(t *testing.T) {
 // BenchmarkStageInteractions benchmarks interaction performance
 func BenchmarkStageInteractions(b *testing.B) {
 	model := &mockREPLForInteractions{mode: "benchmark"}
-	director := NewStageDirectorWithConfig(&testing.T{}, model, StageConfig{
+	director := NewStageDirectorWithConfig(b, model, StageConfig{
 		Timeout:      30 * time.Second,
 		TypingSpeed:  0, // No delay for benchmarking
 		CaptureViews: false, // Disable for performance
@@ -445,4 +532,31 @@ func BenchmarkStageInteractions(b *testing.B) {
 	stats := director.GetSynchronizationStats()
 	b.Logf("Processed %d interactions, %d updates",
 		director.GetStageActionCount(), stats["updates_processed"])
-}
\ No newline at end of file
+}
+
+// TestStageDirector_QuietInBenchmarks tests that a *testing.B drives the stage without snapshots
+func TestStageDirector_QuietInBenchmarks(t *testing.T) {
+	var quiet bool
+	var snapshots int
+
+	testing.Benchmark(func(b *testing.B) {
+		model := &mockREPLForInteractions{mode: "benchmark"}
+		director := NewStageDirectorWithConfig(b, model, StageConfig{
+			Timeout:      5 * time.Second,
+			TypingSpeed:  0,
+			CaptureViews: true,
+			MaxRetries:   0,
+		})
+		defer director.Stop()
+
+		// A fixed amount of work keeps testing.Benchmark from scaling b.N up
+		director.Start()
+		director.Type("abc")
+
+		quiet = director.config.Quiet
+		snapshots = len(director.snapshots)
+	})
+
+	assert.True(t, quiet, "benchmarks should run quietly")
+	assert.Equal(t, 1, snapshots, "only the initial snapshot is captured")
+}
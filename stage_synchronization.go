@@ -0,0 +1,159 @@
+package steadicam
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagedMsg tags a message sent by the director so the model it produces can be identified.
+// The slate held up before each take - every frame is matched back to its scene number.
+type stagedMsg struct {
+	id  int64
+	msg tea.Msg
+}
+
+// teaPackagePath identifies messages that BubbleTea's event loop or renderer handles itself
+var teaPackagePath = reflect.TypeOf(tea.KeyMsg{}).PkgPath()
+
+// shouldStage reports whether a message can be wrapped in a stagedMsg.
+// BubbleTea intercepts its own message types (QuitMsg, BatchMsg, WindowSizeMsg, ...)
+// before or alongside the model's Update, so those must reach the program untouched.
+// Input events are delivered straight to Update and are safe to wrap.
+func shouldStage(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg, tea.FocusMsg, tea.BlurMsg:
+		return true
+	case nil:
+		return false
+	}
+
+	msgType := reflect.TypeOf(msg)
+	for msgType.Kind() == reflect.Ptr {
+		msgType = msgType.Elem()
+	}
+	return msgType.PkgPath() != teaPackagePath
+}
+
+// unwrapStagedMsg returns the original message and its staging ID, or 0 for unstaged messages
+func unwrapStagedMsg(msg tea.Msg) (tea.Msg, int64) {
+	if staged, ok := msg.(stagedMsg); ok {
+		return staged.msg, staged.id
+	}
+	return msg, 0
+}
+
+// errWaitTimeout is returned by waitUntil when the condition never held
+var errWaitTimeout = errors.New("wait timed out")
+
+// subscribe registers for state change notifications from syncModelUpdates.
+// The channel holds at most one pending signal, so a waiter that is busy
+// evaluating its condition never misses the update that arrives meanwhile.
+// The returned function must be called to unsubscribe.
+func (d *StageDirector) subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	d.subscribersMu.Lock()
+	if d.subscribers == nil {
+		d.subscribers = make(map[chan struct{}]struct{})
+	}
+	d.subscribers[ch] = struct{}{}
+	d.subscribersMu.Unlock()
+
+	return ch, func() {
+		d.subscribersMu.Lock()
+		delete(d.subscribers, ch)
+		d.subscribersMu.Unlock()
+	}
+}
+
+// notifyStateChange wakes every subscriber after a new model has been published
+func (d *StageDirector) notifyStateChange() {
+	d.subscribersMu.Lock()
+	defer d.subscribersMu.Unlock()
+
+	for ch := range d.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default: // A signal is already pending
+		}
+	}
+}
+
+// waitUntil blocks until cond holds, the timeout passes, or the stage is cancelled.
+// The condition is evaluated once up front and again each time syncModelUpdates
+// publishes a model, so waits wake on the exact update instead of polling.
+// It returns nil, errWaitTimeout, or the context error.
+func (d *StageDirector) waitUntil(timeout time.Duration, cond func() bool) error {
+	updates, unsubscribe := d.subscribe()
+	defer unsubscribe()
+
+	if cond() {
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-updates:
+			if cond() {
+				return nil
+			}
+		case <-timer.C:
+			return errWaitTimeout
+		case <-d.ctx.Done():
+			return d.ctx.Err()
+		}
+	}
+}
+
+// markMessageObserved records that latestModel now reflects the given staged message.
+// Must be called with modelMu held for writing.
+func (d *StageDirector) markMessageObserved(id int64) {
+	if id > d.observedMessageID {
+		d.observedMessageID = id
+	}
+}
+
+// sendStagedMessage sends a message and blocks until the director has observed
+// the model produced by exactly that message.
+func (d *StageDirector) sendStagedMessage(msg tea.Msg) {
+	id := atomic.AddInt64(&d.nextMessageID, 1)
+
+	observed := func() int64 {
+		d.modelMu.RLock()
+		defer d.modelMu.RUnlock()
+		return d.observedMessageID
+	}
+
+	// Subscribe before sending so the update cannot slip past the wait
+	updates, unsubscribe := d.subscribe()
+	defer unsubscribe()
+
+	d.program.Send(stagedMsg{id: id, msg: msg})
+
+	timer := time.NewTimer(d.config.Timeout)
+	defer timer.Stop()
+
+	for observed() < id {
+		select {
+		case <-updates:
+		case <-timer.C:
+			trip := newStageTrip("SYNC_TIMEOUT", fmt.Sprintf("Model update for %T was not observed", msg), map[string]interface{}{
+				"message_id":       id,
+				"observed_message": observed(),
+				"tea_msg":          fmt.Sprintf("%T: %+v", msg, msg),
+			})
+			d.recordTrip(trip)
+			return
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
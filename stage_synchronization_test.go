@@ -0,0 +1,116 @@
+package steadicam
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tickMsg is a custom message fanned out by burstModel
+type tickMsg int
+
+// burstModel answers every keypress with a burst of follow-up messages
+type burstModel struct {
+	keys  int
+	ticks int
+}
+
+func (m burstModel) Init() tea.Cmd { return nil }
+func (m burstModel) View() string  { return fmt.Sprintf("keys=%d ticks=%d", m.keys, m.ticks) }
+
+func (m burstModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "b" {
+			cmds := make([]tea.Cmd, 200)
+			for i := range cmds {
+				n := tickMsg(i)
+				cmds[i] = func() tea.Msg { return n }
+			}
+			return m, tea.Batch(cmds...)
+		}
+		m.keys++
+	case tickMsg:
+		m.ticks++
+	}
+	return m, nil
+}
+
+// TestStageDirector_LosslessSync tests that no update is dropped and each interaction is observed
+func TestStageDirector_LosslessSync(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, burstModel{}, StageConfig{
+		Timeout:      5 * time.Second,
+		TypingSpeed:  0,
+		CaptureViews: true,
+		MaxRetries:   0,
+		LosslessSync: true,
+	})
+	defer director.Stop()
+
+	director.Start()
+
+	// Every keystroke is reflected as soon as Type returns, without polling for text
+	for i := 1; i <= 5; i++ {
+		director.Type("k")
+		require.Equal(t, i, director.Model().(burstModel).keys)
+	}
+
+	// A burst larger than the update buffer still arrives in full
+	director.Type("b").WaitForText("ticks=200")
+	assert.False(t, director.HasFailed())
+	assert.False(t, director.HasDroppedUpdates())
+
+	stats := director.GetSynchronizationStats()
+	assert.Equal(t, stats["updates_generated"], stats["updates_processed"])
+	assert.Equal(t, int64(6), stats["messages_staged"])
+}
+
+// TestShouldStage tests which messages may be wrapped for lossless tracking
+func TestShouldStage(t *testing.T) {
+	assert.True(t, shouldStage(tea.KeyMsg{Type: tea.KeyEnter}))
+	assert.True(t, shouldStage(tea.MouseMsg{}))
+	assert.True(t, shouldStage(tickMsg(1)))
+
+	// BubbleTea handles its own messages before the model sees them
+	assert.False(t, shouldStage(tea.WindowSizeMsg{Width: 80, Height: 24}))
+	assert.False(t, shouldStage(tea.QuitMsg{}))
+	assert.False(t, shouldStage(tea.BatchMsg{}))
+	assert.False(t, shouldStage(nil))
+}
+
+// TestStageDirector_Subscriptions tests that model updates wake subscribed waits
+func TestStageDirector_Subscriptions(t *testing.T) {
+	director := NewStageDirectorForModelWithConfig(t, burstModel{}, testAdapterConfig)
+	defer director.Stop()
+
+	updates, unsubscribe := director.subscribe()
+	director.notifyStateChange()
+	director.notifyStateChange() // Coalesced with the pending signal
+	assert.Len(t, updates, 1)
+
+	unsubscribe()
+	<-updates
+	director.notifyStateChange()
+	assert.Len(t, updates, 0, "unsubscribed channels are not signalled")
+
+	// A wait wakes on the update that satisfies it rather than on a polling tick
+	director.Start()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		director.program.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	}()
+
+	start := time.Now()
+	err := director.waitUntil(time.Second, func() bool {
+		return director.Model().(burstModel).keys == 1
+	})
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	err = director.waitUntil(10*time.Millisecond, func() bool { return false })
+	assert.Equal(t, errWaitTimeout, err)
+}
@@ -45,6 +45,7 @@ type modelUpdate struct {
 	model     REPLModel // The updated model state
 	sequence  int64     // Unique sequence number for ordering
 	timestamp time.Time // When the update was generated
+	messageID int64     // ID of the staged message that produced it, 0 otherwise
 }
 
 // Closeable defines the interface for models that need resource cleanup
@@ -113,7 +114,7 @@ type REPLModel interface {
 //		t.Fatalf("Stage failed: %s", result.ErrorMessage)
 //	}
 type StageDirector struct {
-	t           *testing.T // Testing context for proper error handling
+	t           testing.TB // Testing context for proper error handling
 	model       REPLModel
 	program     *tea.Program
 	ctx         context.Context
@@ -129,8 +130,9 @@ type StageDirector struct {
 	failed       bool
 
 	// Synchronization
-	updateMu     sync.RWMutex
-	waiting      map[string]chan struct{}
+	updateMu      sync.RWMutex
+	subscribers   map[chan struct{}]struct{} // Waits woken by syncModelUpdates
+	subscribersMu sync.Mutex
 
 	// Model synchronization with atomic sequence tracking
 	modelChan         chan modelUpdate
@@ -138,6 +140,7 @@ type StageDirector struct {
 	modelMu           sync.RWMutex
 	updateSeq         int64 // atomic counter for update ordering
 	lastProcessedSeq  int64 // atomic counter for processed updates
+	sizeSeq           int64 // atomic sequence of the latest update that handled a WindowSizeMsg
 	droppedUpdates    int64 // atomic counter for diagnostic purposes
 
 	// Enhanced metrics for performance monitoring
@@ -147,6 +150,29 @@ type StageDirector struct {
 	sequenceGaps      int64 // atomic counter for sequence gaps detected
 	duplicateUpdates  int64 // atomic counter for duplicate/out-of-order updates
 
+	// Lossless synchronization: each staged message is matched to its model update
+	nextMessageID     int64 // atomic counter for staged message IDs
+	observedMessageID int64 // latest staged message reflected in latestModel, guarded by modelMu
+
+	// Virtual terminal fed by BubbleTea's real renderer
+	screen        *Screen
+	lastUpdateAt  time.Time // When the latest model was produced, guarded by modelMu
+
+	// Where the simulated mouse pointer last was, for scrolling under it
+	pointer Point
+
+	// Virtual clock driving the model's timers, nil for wall-clock stages
+	clock *FakeClock
+
+	// Commands returned by the model, with the stubs and delays applied to them
+	commands commandLog
+
+	// WindowSizeMsgs sent only to size the renderer; the model already has them
+	rendererOnlySizes int32 // atomic counter
+
+	// Timestamped copy of the renderer's output, nil for quiet stages
+	output *outputLog
+
 	// Configuration
 	config  StageConfig
 	started bool
@@ -157,7 +183,9 @@ type StageDirector struct {
 // Stanley's camera crew - captures every scene transition with precision
 type stageModelWrapper struct {
 	REPLModel
-	director *StageDirector
+	director    *StageDirector
+	initCmd     tea.Cmd // Commands from an Init the stage already ran
+	initialized bool    // Init ran before the program started; return initCmd instead
 }
 
 // StageAction records a single interaction with the REPL during staging
@@ -177,6 +205,8 @@ type StageSnapshot struct {
 	View      string    // The rendered view content
 	Mode      string    // Application mode at capture time
 	Input     string    // User input at capture time
+	Label     string    // Tracking shot label, empty for automatic snapshots
+	Frame     string    // Filename of the tracking shot rendered from this snapshot
 }
 
 // StageResult contains the complete results of an interactive stage session.
@@ -197,6 +227,7 @@ type StageSnapshot struct {
 //	}
 type StageResult struct {
 	Actions      []StageAction   // All interactions performed
+	Commands     []IssuedCommand // Commands the model issued, in order
 	Snapshots    []StageSnapshot // View snapshots captured
 	Success      bool            // Whether stage completed without errors
 	Duration     time.Duration   // Total stage execution time
@@ -204,6 +235,25 @@ type StageResult struct {
 	Error        error           // Structured error for programmatic handling
 	ErrorDetails string          // Detailed technical error information for debugging
 	TripReport   string          // Detailed trip handling report
+
+	StartedAt time.Time        // When the stage started
+	Width     int              // Terminal width the stage started with
+	Height    int              // Terminal height the stage started with
+	Output    []TerminalOutput // Raw renderer output, for WriteCast; empty for quiet stages
+}
+
+// TrackingShots returns the snapshots that produced film frames, in capture order.
+//
+// Each returned snapshot has Frame set to the image filename, which lets reports
+// pair every screenshot with the view, mode and input it was rendered from.
+func (r *StageResult) TrackingShots() []StageSnapshot {
+	var shots []StageSnapshot
+	for _, snapshot := range r.Snapshots {
+		if snapshot.Frame != "" {
+			shots = append(shots, snapshot)
+		}
+	}
+	return shots
 }
 
 // newStageTrip creates a new trip for stage errors
@@ -239,6 +289,24 @@ type StageConfig struct {
 	CaptureViews bool
 	// MaxRetries for transient operations (future use)
 	MaxRetries int
+	// LosslessSync never drops model updates: the program blocks until the director
+	// has consumed each one, and every interaction waits until the director has
+	// observed the model produced by that exact message.
+	LosslessSync bool
+	// Quiet suppresses [TRACE] logging and automatic interaction snapshots.
+	// It is enabled automatically when the director is driven by a *testing.B,
+	// so benchmark timings measure the application rather than the logging.
+	// testing.B doesn't say whether its timer is running, so a benchmark's
+	// director stays quiet for its whole life, setup and teardown included.
+	Quiet bool
+	// Width and Height set the terminal size in cells. When both are set the model
+	// receives a tea.WindowSizeMsg after Init, before its first view; otherwise the virtual
+	// terminal is 80x24 and no size is ever sent.
+	Width  int
+	Height int
+	// SettleWindow is how long the model must go without messages, running
+	// commands or view changes for WaitForIdle to return (default 50ms)
+	SettleWindow time.Duration
 }
 
 // DefaultStageConfig returns a StageConfig with sensible defaults.
@@ -264,7 +332,7 @@ func DefaultStageConfig() StageConfig {
 // simulating user interactions and asserting application state.
 //
 // Parameters:
-//   - t: The testing.T instance for error reporting
+//   - t: The testing.T, testing.B or testing.F instance for error reporting
 //   - model: A REPLModel implementation of your BubbleTea application
 //
 // Returns a director ready to be configured and started. You must call Start()
@@ -274,7 +342,7 @@ func DefaultStageConfig() StageConfig {
 //
 //	director := NewStageDirector(t, myModel)
 //	result := director.Start().Type("hello").Stop()
-func NewStageDirector(t *testing.T, model REPLModel) *StageDirector {
+func NewStageDirector(t testing.TB, model REPLModel) *StageDirector {
 	return NewStageDirectorWithConfig(t, model, DefaultStageConfig())
 }
 
@@ -284,7 +352,7 @@ func NewStageDirector(t *testing.T, model REPLModel) *StageDirector {
 // behavior. For most cases, NewStageDirector with defaults is sufficient.
 //
 // Parameters:
-//   - t: The testing.T instance for error reporting
+//   - t: The testing.T, testing.B or testing.F instance for error reporting
 //   - model: A REPLModel implementation of your BubbleTea application
 //   - config: Custom configuration for director behavior
 //
@@ -295,9 +363,14 @@ func NewStageDirector(t *testing.T, model REPLModel) *StageDirector {
 //		TypingSpeed: 0, // No typing delay
 //	}
 //	director := NewStageDirectorWithConfig(t, model, config)
-func NewStageDirectorWithConfig(t *testing.T, model REPLModel, config StageConfig) *StageDirector {
+func NewStageDirectorWithConfig(t testing.TB, model REPLModel, config StageConfig) *StageDirector {
 	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
 
+	// Benchmarks run the stage b.N times - keep the hot path free of logging
+	if _, isBenchmark := t.(*testing.B); isBenchmark {
+		config.Quiet = true
+	}
+
 	// Create trip handler for error management
 	tripHandler := trip.NewHandler("stage_director", trip.DefaultPolicy())
 
@@ -308,7 +381,7 @@ func NewStageDirectorWithConfig(t *testing.T, model REPLModel, config StageConfi
 		cancel:       cancel,
 		interactions: make([]StageAction, 0),
 		snapshots:    make([]StageSnapshot, 0),
-		waiting:      make(map[string]chan struct{}),
+		subscribers:  make(map[chan struct{}]struct{}),
 		config:       config,
 		started:      false,
 		modelChan:         make(chan modelUpdate, 50), // Larger buffer with update metadata
@@ -322,10 +395,8 @@ func NewStageDirectorWithConfig(t *testing.T, model REPLModel, config StageConfi
 		sequenceGaps:      0,
 		duplicateUpdates:  0,
 		tripHandler:  tripHandler,
+		screen:       NewScreen(config.terminalSize()),
 	}
 
-	// Start model synchronization goroutine
-	go director.syncModelUpdates()
-
 	return director
 }
\ No newline at end of file
@@ -0,0 +1,162 @@
+package steadicam
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// svgFontFamily prefers common monospace fonts so cells line up in any viewer
+const svgFontFamily = `ui-monospace, 'SF Mono', Menlo, 'DejaVu Sans Mono', Consolas, monospace`
+
+// Default SVG font size when the rendering config leaves it unset
+const defaultSVGFontSize = 14
+
+// svgRun is a stretch of adjacent cells in one row sharing a style
+type svgRun struct {
+	col   int
+	text  string
+	cells int
+	style CellStyle
+	wide  bool // The run is a single wide character
+}
+
+// CaptureSVG saves the current frame as an SVG image: crisp at any size,
+// searchable and copyable in a browser, and diffable in review, where the PNG
+// from CaptureFrame is a bitmap.
+func (rs *RenderingStage) CaptureSVG(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := rs.WriteSVG(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteSVG writes the current frame as SVG. Each row is a <text> element with
+// one <tspan> per run of identically styled cells, positioned on the cell grid;
+// non-default backgrounds are <rect>s behind the text. Output is deterministic,
+// one line per row, so frames diff cleanly.
+func (rs *RenderingStage) WriteSVG(w io.Writer) error {
+	fontSize := float64(rs.config.FontSize)
+	if fontSize <= 0 {
+		fontSize = defaultSVGFontSize
+	}
+	cellWidth := fontSize * 0.6
+	lineHeight := fontSize * 1.2
+
+	cells := rs.screen.Cells()
+	cols, rows := rs.screen.Size()
+	width, height := float64(cols)*cellWidth, float64(rows)*lineHeight
+
+	// Ask for the configured font first, when viewers have it installed
+	family := svgFontFamily
+	if rs.fontFamily != "" {
+		family = "'" + rs.fontFamily + "', " + family
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %[1]s %[2]s" font-family="%s" font-size="%s">`+"\n",
+		svgNumber(width), svgNumber(height), html.EscapeString(family), svgNumber(fontSize))
+	fmt.Fprintf(out, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(rs.config.Background))
+
+	// Backgrounds first, so text is drawn over them
+	for row, line := range cells {
+		for _, run := range svgRuns(line) {
+			_, bg := run.style.Colors(rs.config.Foreground, rs.config.Background)
+			if bg == rs.config.Background {
+				continue
+			}
+			fmt.Fprintf(out, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+				svgNumber(float64(run.col)*cellWidth), svgNumber(float64(row)*lineHeight),
+				svgNumber(float64(run.cells)*cellWidth), svgNumber(lineHeight), svgColor(bg))
+		}
+	}
+
+	fmt.Fprintf(out, `<g fill="%s" xml:space="preserve" style="white-space:pre">`+"\n", svgColor(rs.config.Foreground))
+	for row, line := range cells {
+		runs := svgRuns(line)
+		if len(runs) == 0 {
+			continue
+		}
+
+		// Place the baseline about a fifth of a line above the cell's bottom edge
+		baseline := float64(row+1)*lineHeight - lineHeight*0.22
+		fmt.Fprintf(out, `<text y="%s">`, svgNumber(baseline))
+		for _, run := range runs {
+			if strings.TrimSpace(run.text) == "" && !run.style.Underline {
+				continue
+			}
+			fg, _ := run.style.Colors(rs.config.Foreground, rs.config.Background)
+			fmt.Fprintf(out, `<tspan x="%s"%s>%s</tspan>`,
+				svgNumber(float64(run.col)*cellWidth), svgStyleAttributes(run.style, fg, rs.config.Foreground), html.EscapeString(run.text))
+		}
+		out.WriteString("</text>\n")
+	}
+	out.WriteString("</g>\n</svg>\n")
+	return out.Flush()
+}
+
+// svgRuns splits a row into styled runs, leaving out trailing blank cells.
+// Wide characters get a run of their own and end the run before them, so the
+// text after one is positioned on the grid whatever width a viewer's font
+// gives it.
+func svgRuns(line []Cell) []svgRun {
+	end := len(line)
+	for end > 0 && line[end-1] == emptyCell {
+		end--
+	}
+
+	var runs []svgRun
+	for col := 0; col < end; col++ {
+		cell := line[col]
+		if cell.IsSpacer() && len(runs) > 0 {
+			runs[len(runs)-1].cells++
+			continue
+		}
+		if n := len(runs); n > 0 && runs[n-1].style == cell.Style && cell.Width == 1 && !runs[n-1].wide {
+			runs[n-1].text += cell.Grapheme
+			runs[n-1].cells++
+			continue
+		}
+		runs = append(runs, svgRun{col: col, text: cell.Grapheme, cells: 1, style: cell.Style, wide: cell.Width > 1})
+	}
+	return runs
+}
+
+// svgStyleAttributes returns the attributes a run needs beyond the defaults
+func svgStyleAttributes(style CellStyle, fg, defaultFg color.RGBA) string {
+	var attrs strings.Builder
+	if fg != defaultFg {
+		fmt.Fprintf(&attrs, ` fill="%s"`, svgColor(fg))
+	}
+	if style.Bold {
+		attrs.WriteString(` font-weight="bold"`)
+	}
+	if style.Italic {
+		attrs.WriteString(` font-style="italic"`)
+	}
+	if style.Underline {
+		attrs.WriteString(` text-decoration="underline"`)
+	}
+	return attrs.String()
+}
+
+// svgColor formats a color as #rrggbb
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// svgNumber formats a coordinate to two decimals, without trailing zeros
+func svgNumber(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,78 @@
+package steadicam
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRenderingStage_WriteSVG tests styled runs, backgrounds and escaping in SVG output
+func TestRenderingStage_WriteSVG(t *testing.T) {
+	stage := NewRenderingStage(Config{
+		Width:      30,
+		Height:     3,
+		FontSize:   10,
+		Background: color.RGBA{0, 0, 0, 255},
+		Foreground: color.RGBA{255, 255, 255, 255},
+	})
+	stage.RenderText("plain \x1b[1;31mbold red\x1b[0m <&>\n\x1b[44m  blue  \x1b[0m \x1b[4;3mlink\x1b[0m")
+
+	var buf bytes.Buffer
+	require.NoError(t, stage.WriteSVG(&buf))
+	svg := buf.String()
+
+	// Well-formed XML that sizes itself to the cell grid
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err != nil {
+			require.ErrorContains(t, err, "EOF")
+			break
+		}
+	}
+	assert.Contains(t, svg, `width="180" height="36" viewBox="0 0 180 36"`)
+
+	lines := strings.Split(strings.TrimSpace(svg), "\n")
+	assert.Contains(t, lines, `<rect x="0" y="12" width="48" height="12" fill="#0000ee"/>`)
+	assert.Contains(t, lines,
+		`<text y="9.36"><tspan x="0">plain </tspan><tspan x="36" fill="#cd0000" font-weight="bold">bold red</tspan><tspan x="84"> &lt;&amp;&gt;</tspan></text>`)
+	assert.Contains(t, lines,
+		`<text y="21.36"><tspan x="0">  blue  </tspan><tspan x="54" font-style="italic" text-decoration="underline">link</tspan></text>`)
+	assert.Equal(t, 2, strings.Count(svg, "<text "), "blank rows are left out")
+}
+
+// TestOperator_ScreenshotSVG tests that .svg screenshots are written as SVG
+func TestOperator_ScreenshotSVG(t *testing.T) {
+	op := NewOperator(t, &mockREPLForInteractions{input: "svg"}, t.TempDir())
+	path := filepath.Join(t.TempDir(), "docs", "repl.svg")
+
+	op.Start().Screenshot(path)
+	result := op.Stop()
+	require.True(t, result.Success, result.ErrorMessage)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "<svg "))
+	assert.Contains(t, string(data), ">Mock REPL: svg</tspan>")
+
+	// SVG shots are skipped by bitmap animations
+	assert.ErrorContains(t, result.WriteGIF(&bytes.Buffer{}, AnimationOptions{}), "no tracking shots")
+}
+
+// TestSVGRuns_Wide tests that wide characters get runs of their own
+func TestSVGRuns_Wide(t *testing.T) {
+	screen := NewScreen(8, 1)
+	screen.WriteString("a漢bc")
+
+	runs := svgRuns(screen.Cells()[0])
+	require.Len(t, runs, 3)
+	assert.Equal(t, svgRun{col: 0, text: "a", cells: 1}, runs[0])
+	assert.Equal(t, svgRun{col: 1, text: "漢", cells: 2, wide: true}, runs[1])
+	assert.Equal(t, svgRun{col: 3, text: "bc", cells: 2}, runs[2])
+}
@@ -1,7 +1,8 @@
 package steadicam
 
 import (
-	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -49,63 +50,48 @@ func (m *BenchmarkModel) CheckCondition(condition string) bool {
 	return condition == "benchmark"
 }
 
-// BenchmarkModelUpdateProcessing measures the performance of model update processing
-// Tests the core synchronization path including atomic operations and channel sends
-func BenchmarkModelUpdateProcessing(b *testing.B) {
-	model := &BenchmarkModel{mode: "benchmark"}
+// newBenchmarkDirector starts a stage on model the way benchmarks drive it
+func newBenchmarkDirector(b *testing.B, model REPLModel, timeout time.Duration) *StageDirector {
 	director := NewStageDirectorWithConfig(
-		&testing.T{}, // We need a *testing.T for constructor
+		b,
 		model,
 		StageConfig{
-			Timeout:      time.Second,
+			Timeout:      timeout,
 			CaptureViews: false, // Disable for performance
 		},
 	)
+	return director.Start()
+}
 
-	// Start the director to initialize synchronization
-	director.Start()
+// BenchmarkModelUpdateProcessing measures the performance of model update processing
+// Tests the core synchronization path including atomic operations and channel sends
+func BenchmarkModelUpdateProcessing(b *testing.B) {
+	director := newBenchmarkDirector(b, &BenchmarkModel{mode: "benchmark"}, time.Minute)
 	defer director.Stop()
 
-	// Wait for initialization
-	time.Sleep(10 * time.Millisecond)
-
 	b.ResetTimer()
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
 		// Simulate a keystroke update
-		director.sendMessage(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		director.Type("a")
 	}
 }
 
 // BenchmarkHighVolumeUpdates tests performance under high update volume
 // Simulates rapid user input or automated test scenarios
 func BenchmarkHighVolumeUpdates(b *testing.B) {
-	model := &BenchmarkModel{mode: "benchmark"}
-	director := NewStageDirectorWithConfig(
-		&testing.T{},
-		model,
-		StageConfig{
-			Timeout:      time.Second,
-			CaptureViews: false,
-		},
-	)
-
-	director.Start()
+	director := newBenchmarkDirector(b, &BenchmarkModel{mode: "benchmark"}, time.Minute)
 	defer director.Stop()
 
-	// Wait for initialization
-	time.Sleep(10 * time.Millisecond)
-
 	b.ResetTimer()
 	b.ReportAllocs()
 
 	// Send updates in batches to test buffer utilization
 	batchSize := 10
 	for i := 0; i < b.N; i += batchSize {
-		for j := 0; j < batchSize && i+j < b.N; j++ {
-			director.sendMessage(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
-		}
+		n := min(batchSize, b.N-i)
+		director.Type(strings.Repeat("x", n))
 		// Brief pause to simulate realistic input patterns
 		time.Sleep(time.Microsecond)
 	}
@@ -114,44 +100,20 @@ func BenchmarkHighVolumeUpdates(b *testing.B) {
 // BenchmarkBufferOverflowScenario tests behavior when channel buffer is full
 // Important for understanding graceful degradation characteristics
 func BenchmarkBufferOverflowScenario(b *testing.B) {
-	model := &BenchmarkModel{mode: "benchmark"}
-
-	// Use smaller buffer for this test to trigger overflow conditions
-	director := &StageDirector{
-		t:                &testing.T{},
-		model:           model,
-		ctx:             context.Background(),
-		cancel:          func() {},
-		interactions:    make([]StageAction, 0),
-		snapshots:       make([]StageSnapshot, 0),
-		waiting:         make(map[string]chan struct{}),
-		config:          StageConfig{Timeout: time.Second, CaptureViews: false},
-		modelChan:       make(chan modelUpdate, 5), // Small buffer for overflow testing
-		latestModel:     model,
-		updateSeq:       0,
-		lastProcessedSeq: 0,
-		droppedUpdates:   0,
-		updatesSent:      0,
-		updatesProcessed: 0,
-		bufferOverflows:  0,
-	}
-
-	// Start sync goroutine manually for this test
-	go director.syncModelUpdates()
-	defer director.cancel()
+	director := NewStageDirectorForModelWithConfig(b, burstModel{}, StageConfig{
+		Timeout:      time.Minute,
+		CaptureViews: false,
+	})
+	director.Start()
+	defer director.Stop()
 
 	b.ResetTimer()
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		// Create wrapper to test overflow behavior
-		wrapper := stageModelWrapper{
-			REPLModel: model,
-			director:  director,
-		}
-
-		// Send rapid updates to trigger buffer overflow
-		wrapper.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+		// Each "b" makes the model emit 200 messages at once, more than the
+		// update buffer holds, so the stage has to drop intermediate models
+		director.Press("b").WaitForText(fmt.Sprintf("ticks=%d", 200*(i+1)))
 	}
 
 	// Report overflow statistics
@@ -163,72 +125,56 @@ func BenchmarkBufferOverflowScenario(b *testing.B) {
 // BenchmarkConcurrentAccess measures performance under concurrent read/write access
 // Tests the RWMutex performance for model state access
 func BenchmarkConcurrentAccess(b *testing.B) {
-	model := &BenchmarkModel{mode: "benchmark"}
-	director := NewStageDirectorWithConfig(
-		&testing.T{},
-		model,
-		StageConfig{
-			Timeout:      time.Second,
-			CaptureViews: false,
-		},
-	)
-
-	director.Start()
+	director := newBenchmarkDirector(b, &BenchmarkModel{mode: "benchmark"}, time.Minute)
 	defer director.Stop()
 
-	// Wait for initialization
-	time.Sleep(10 * time.Millisecond)
+	// One writer types while the benchmark reads, as a test and the program would
+	done := make(chan struct{})
+	typing := make(chan struct{})
+	go func() {
+		defer close(typing)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				director.Type("c")
+			}
+		}
+	}()
 
 	b.ResetTimer()
 	b.ReportAllocs()
 
-	// Run concurrent readers and writers
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			// Mix of read and write operations
-			switch b.N % 4 {
-			case 0, 1, 2:
-				// 75% read operations
-				_ = director.getCurrentView()
-				_ = director.getCurrentInput()
-				_ = director.getCurrentMode()
-			case 3:
-				// 25% write operations (updates)
-				director.sendMessage(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
-			}
+			_ = director.Model().View()
+			_ = director.Screen().Lines()
+			_ = director.GetLatestSnapshot()
 		}
 	})
+
+	b.StopTimer()
+	close(done)
+	<-typing
 }
 
 // BenchmarkMemoryAllocation measures memory allocation patterns
 // Important for understanding GC pressure in long-running tests
 func BenchmarkMemoryAllocation(b *testing.B) {
-	model := &BenchmarkModel{mode: "benchmark"}
-	director := NewStageDirectorWithConfig(
-		&testing.T{},
-		model,
-		StageConfig{
-			Timeout:      time.Second,
-			CaptureViews: false,
-		},
-	)
-
-	director.Start()
+	director := newBenchmarkDirector(b, &BenchmarkModel{mode: "benchmark"}, time.Minute)
 	defer director.Stop()
 
-	// Wait for initialization
-	time.Sleep(10 * time.Millisecond)
-
 	b.ResetTimer()
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
 		// Test various operations that allocate memory
-		director.sendMessage(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+		director.Type("m")
 
 		// Occasional state queries to test read allocations
 		if i%10 == 0 {
-			_ = director.getCurrentView()
+			_ = director.Model().View()
 			_ = director.GetLatestSnapshot()
 		}
 	}
@@ -237,34 +183,15 @@ func BenchmarkMemoryAllocation(b *testing.B) {
 // BenchmarkSequenceTracking measures the overhead of sequence number tracking
 // Tests atomic operations performance under load
 func BenchmarkSequenceTracking(b *testing.B) {
-	model := &BenchmarkModel{mode: "benchmark"}
-	director := NewStageDirectorWithConfig(
-		&testing.T{},
-		model,
-		StageConfig{
-			Timeout:      time.Second,
-			CaptureViews: false,
-		},
-	)
-
-	director.Start()
+	director := newBenchmarkDirector(b, &BenchmarkModel{mode: "benchmark"}, time.Minute)
 	defer director.Stop()
 
-	// Wait for initialization
-	time.Sleep(10 * time.Millisecond)
-
 	b.ResetTimer()
 	b.ReportAllocs()
 
-	// Measure atomic sequence operations
+	// Every keystroke is one sequenced update
 	for i := 0; i < b.N; i++ {
-		wrapper := stageModelWrapper{
-			REPLModel: model,
-			director:  director,
-		}
-
-		// This will trigger atomic sequence increment and tracking
-		wrapper.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+		director.Press("s")
 	}
 
 	// Report sequence statistics
@@ -280,22 +207,12 @@ func BenchmarkContextCancellation(b *testing.B) {
 	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		model := &BenchmarkModel{mode: "benchmark"}
-		director := NewStageDirectorWithConfig(
-			&testing.T{},
-			model,
-			StageConfig{
-				Timeout:      100 * time.Millisecond,
-				CaptureViews: false,
-			},
-		)
-
-		director.Start()
+		director := newBenchmarkDirector(b, &BenchmarkModel{mode: "benchmark"}, 100*time.Millisecond)
 
 		// Send a few updates
-		director.sendMessage(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+		director.Type("c")
 
 		// Measure cleanup time
 		director.Stop()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,415 @@
+package steadicam
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sbvh/qntx/cmd/repl/bubble/steadicam/trip"
+	"gopkg.in/yaml.v3"
+)
+
+// Tape is a shooting script for a stage: which model to run, how to configure
+// the stage, and the steps to perform, each remembering its line in the file.
+//
+// Tapes let people who do not write Go script a stage in YAML:
+//
+//	model: search-repl
+//	config:
+//	  timeout: 5s
+//	  typing_speed: 0s
+//	  width: 100
+//	  height: 30
+//	steps:
+//	  - type: "hello"
+//	  - press: enter
+//	  - wait_for_mode: results
+//	  - assert_view: "Found 3"
+//	  - capture: results
+//	  - resize: 60x20
+type Tape struct {
+	Name   string     // File the tape was read from, used in failure messages
+	Model  string     // Name the model factory was registered under
+	Config TapeConfig // Stage settings
+	Steps  []TapeStep // Steps in the order they run
+}
+
+// TapeConfig holds the stage settings a tape may override.
+// Unset fields keep DefaultStageConfig values.
+type TapeConfig struct {
+	Timeout      *time.Duration `yaml:"timeout"`
+	TypingSpeed  *time.Duration `yaml:"typing_speed"`
+	SettleWindow time.Duration  `yaml:"settle_window"`
+	LosslessSync bool           `yaml:"lossless_sync"`
+	Width        int            `yaml:"width"`
+	Height       int            `yaml:"height"`
+	OutputDir    string         `yaml:"output_dir"` // Where capture steps write frames; a temp dir if empty
+}
+
+// TapeStep is one action of a tape
+type TapeStep struct {
+	Line   int      // Line of the step in the tape file
+	Action string   // Step name, such as "press" or "wait_for_text"
+	Args   []string // Step arguments as written
+}
+
+// String describes the step for failure messages
+func (s TapeStep) String() string {
+	if len(s.Args) == 0 {
+		return s.Action
+	}
+	return fmt.Sprintf("%s %q", s.Action, strings.Join(s.Args, " "))
+}
+
+// ModelFactory builds a fresh model for each tape run
+type ModelFactory func() REPLModel
+
+var (
+	modelRegistryMu sync.RWMutex
+	modelRegistry   = make(map[string]ModelFactory)
+)
+
+// RegisterModel makes a model available to tapes under name, typically from an
+// init function in the package's tests. Like database/sql.Register it panics if
+// the factory is nil or the name is already taken.
+//
+// Example:
+//
+//	func init() {
+//		steadicam.RegisterModel("search-repl", func() steadicam.REPLModel { return NewSearchREPL() })
+//	}
+func RegisterModel(name string, factory ModelFactory) {
+	modelRegistryMu.Lock()
+	defer modelRegistryMu.Unlock()
+
+	if factory == nil {
+		panic("steadicam: RegisterModel factory is nil")
+	}
+	if _, taken := modelRegistry[name]; taken {
+		panic("steadicam: RegisterModel called twice for model " + name)
+	}
+	modelRegistry[name] = factory
+}
+
+// registeredModel looks up a model factory by name
+func registeredModel(name string) (ModelFactory, bool) {
+	modelRegistryMu.RLock()
+	defer modelRegistryMu.RUnlock()
+	factory, ok := modelRegistry[name]
+	return factory, ok
+}
+
+// registeredModelNames lists registered models for error messages
+func registeredModelNames() []string {
+	modelRegistryMu.RLock()
+	defer modelRegistryMu.RUnlock()
+
+	names := make([]string, 0, len(modelRegistry))
+	for name := range modelRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunTape loads a tape file and runs it, failing t with the tape's file and
+// line number if the file is invalid or a step trips.
+//
+// Example:
+//
+//	func TestSearchTapes(t *testing.T) {
+//		result := steadicam.RunTape(t, "testdata/search.tape.yaml")
+//		assert.True(t, result.Success)
+//	}
+func RunTape(t testing.TB, path string) *StageResult {
+	t.Helper()
+
+	tape, err := LoadTape(path)
+	if err != nil {
+		t.Errorf("%v", err)
+		return &StageResult{ErrorMessage: err.Error(), Error: err}
+	}
+	return tape.Run(t)
+}
+
+// LoadTape reads and validates a YAML tape file
+func LoadTape(path string) (*Tape, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTape(path, data)
+}
+
+// tapeFile is the YAML layout of a tape
+type tapeFile struct {
+	Model  string      `yaml:"model"`
+	Config TapeConfig  `yaml:"config"`
+	Steps  []yaml.Node `yaml:"steps"`
+}
+
+// ParseTape parses a YAML tape. Name identifies the tape in error messages,
+// which are prefixed with "name:line:" like compiler errors.
+func ParseTape(name string, data []byte) (*Tape, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var file tapeFile
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if file.Model == "" {
+		return nil, fmt.Errorf("%s:1: tape does not name a model", name)
+	}
+
+	tape := &Tape{Name: name, Model: file.Model, Config: file.Config}
+	for i := range file.Steps {
+		step, err := parseTapeStep(&file.Steps[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, file.Steps[i].Line, err)
+		}
+		tape.Steps = append(tape.Steps, step)
+	}
+	return tape, nil
+}
+
+// parseTapeStep reads a single-key mapping such as `press: enter`
+func parseTapeStep(node *yaml.Node) (TapeStep, error) {
+	if node.Kind != yaml.MappingNode || len(node.Content) != 2 {
+		return TapeStep{}, errors.New("a step is a single `action: argument` pair")
+	}
+	key, value := node.Content[0], node.Content[1]
+	step := TapeStep{Line: key.Line, Action: key.Value}
+
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Tag != "!!null" {
+			step.Args = []string{value.Value}
+		}
+	case yaml.SequenceNode:
+		for _, item := range value.Content {
+			if item.Kind != yaml.ScalarNode {
+				return TapeStep{}, fmt.Errorf("%s arguments must be plain values", step.Action)
+			}
+			step.Args = append(step.Args, item.Value)
+		}
+	default:
+		return TapeStep{}, fmt.Errorf("%s takes a value or a list of values", step.Action)
+	}
+
+	return step, validateTapeStep(step)
+}
+
+// tapeActions lists every step a tape can use with the number of arguments it takes
+var tapeActions = map[string]struct{ min, max int }{
+	"type":           {1, 1},
+	"paste":          {1, 1},
+	"press":          {1, -1},
+	"wait":           {1, 1},
+	"wait_for_text":  {1, 1},
+	"wait_for_mode":  {1, 1},
+	"wait_for_match": {1, 1},
+	"wait_for_idle":  {0, 0},
+	"assert_view":    {1, 1},
+	"assert_mode":    {1, 1},
+	"capture":        {1, 1},
+	"screenshot":     {1, 1},
+	"resize":         {1, 2},
+}
+
+// validateTapeStep checks a step's name and arguments before anything runs
+func validateTapeStep(step TapeStep) error {
+	arity, ok := tapeActions[step.Action]
+	if !ok {
+		names := make([]string, 0, len(tapeActions))
+		for name := range tapeActions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown step %q (known steps: %s)", step.Action, strings.Join(names, ", "))
+	}
+	if len(step.Args) < arity.min || (arity.max >= 0 && len(step.Args) > arity.max) {
+		return fmt.Errorf("%s takes %s", step.Action, describeArity(arity.min, arity.max))
+	}
+
+	switch step.Action {
+	case "press":
+		for _, key := range strings.Fields(strings.Join(step.Args, " ")) {
+			if _, err := ParseKey(key); err != nil {
+				return err
+			}
+		}
+	case "wait":
+		if _, err := time.ParseDuration(step.Args[0]); err != nil {
+			return err
+		}
+	case "wait_for_match":
+		if _, err := regexp.Compile(step.Args[0]); err != nil {
+			return err
+		}
+	case "resize":
+		if _, _, err := parseTapeSize(step.Args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeArity phrases an argument count for error messages
+func describeArity(min, max int) string {
+	switch {
+	case max == 0:
+		return "no argument"
+	case min == max && min == 1:
+		return "one argument"
+	case max < 0:
+		return fmt.Sprintf("at least %d argument", min)
+	}
+	return fmt.Sprintf("%d to %d arguments", min, max)
+}
+
+// parseTapeSize accepts "100x30" or a [100, 30] list
+func parseTapeSize(args []string) (int, int, error) {
+	parts := args
+	if len(args) == 1 {
+		parts = strings.Split(strings.ToLower(args[0]), "x")
+	}
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("size must be COLSxROWS, got %q", strings.Join(args, " "))
+	}
+	cols, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid columns %q", parts[0])
+	}
+	rows, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rows %q", parts[1])
+	}
+	return cols, rows, nil
+}
+
+// stageConfig applies the tape's settings over the defaults
+func (c TapeConfig) stageConfig() StageConfig {
+	config := DefaultStageConfig()
+	if c.Timeout != nil {
+		config.Timeout = *c.Timeout
+	}
+	if c.TypingSpeed != nil {
+		config.TypingSpeed = *c.TypingSpeed
+	}
+	config.SettleWindow = c.SettleWindow
+	config.LosslessSync = c.LosslessSync
+	config.Width, config.Height = c.Width, c.Height
+	return config
+}
+
+// Run stages the tape's model and performs its steps in order.
+//
+// The first step that trips stops the run. Its trip message is prefixed with
+// the tape's file and line, and gains a "tape_line" context entry, and t fails
+// with the same location, so a broken step reads like a compiler error
+// pointing into the tape.
+func (tape *Tape) Run(t testing.TB) *StageResult {
+	t.Helper()
+
+	factory, ok := registeredModel(tape.Model)
+	if !ok {
+		err := fmt.Errorf("%s:1: unknown model %q (registered: %s)", tape.Name, tape.Model, strings.Join(registeredModelNames(), ", "))
+		t.Errorf("%v", err)
+		return &StageResult{ErrorMessage: err.Error(), Error: err}
+	}
+	return tape.RunModel(t, factory())
+}
+
+// RunModel performs the tape's steps against model, ignoring the tape's Model
+// name. VHS tapes, which do not name a model, are run this way.
+func (tape *Tape) RunModel(t testing.TB, model REPLModel) *StageResult {
+	t.Helper()
+
+	outputDir := tape.Config.OutputDir
+	if outputDir == "" {
+		outputDir = t.TempDir()
+	}
+
+	op := newOperator(NewStageDirectorWithConfig(t, model, tape.Config.stageConfig()), outputDir)
+	op.Start()
+	tape.runSteps(t, op)
+	return op.Stop()
+}
+
+// runSteps performs steps until one trips, reporting it against the tape
+func (tape *Tape) runSteps(t testing.TB, op *Operator) {
+	t.Helper()
+
+	for _, step := range tape.Steps {
+		if op.failed {
+			return
+		}
+
+		before := op.lastTrip
+		op.runTapeStep(step)
+		if op.lastTrip != before && op.lastTrip != nil {
+			location := fmt.Sprintf("%s:%d", tape.Name, step.Line)
+			t.Errorf("%s: %s: %s", location, step, op.lastTrip.Message)
+			if op.lastTrip.Context == nil {
+				op.lastTrip.Context = trip.Context{}
+			}
+			op.lastTrip.Context["tape_line"] = location
+			op.lastTrip.Message = location + ": " + op.lastTrip.Message
+			return
+		}
+	}
+}
+
+// runTapeStep performs a single validated step
+func (op *Operator) runTapeStep(step TapeStep) {
+	switch step.Action {
+	case "type":
+		op.Type(step.Args[0])
+	case "paste":
+		op.Paste(step.Args[0])
+	case "press":
+		op.PressSequence(strings.Join(step.Args, " "))
+	case "wait":
+		duration, _ := time.ParseDuration(step.Args[0])
+		op.Wait(duration)
+	case "wait_for_text":
+		op.WaitForText(step.Args[0])
+	case "wait_for_mode":
+		op.WaitForMode(step.Args[0])
+	case "wait_for_match":
+		op.WaitForMatch(step.Args[0])
+	case "wait_for_idle":
+		op.WaitForIdle()
+	case "assert_view":
+		op.AssertViewContains(step.Args[0])
+	case "assert_mode":
+		op.AssertMode(step.Args[0])
+	case "capture":
+		op.CaptureTrackingShot(sanitizeFrameLabel(step.Args[0]))
+	case "screenshot":
+		op.Screenshot(step.Args[0])
+	case "resize":
+		cols, rows, _ := parseTapeSize(step.Args)
+		op.Resize(cols, rows)
+	}
+}
+
+// sanitizeFrameLabel keeps capture labels usable as part of a file name
+func sanitizeFrameLabel(label string) string {
+	return strings.Map(func(r rune) rune {
+		if r == filepath.Separator || r == '/' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, label)
+}
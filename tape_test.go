@@ -0,0 +1,112 @@
+package steadicam
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	RegisterModel("tape-echo", func() REPLModel { return &mockREPLForInteractions{mode: "ready"} })
+}
+
+// recordingTB captures failures so tests can check what a tape reports
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// TestRunTape_File runs a tape from testdata end to end
+func TestRunTape_File(t *testing.T) {
+	result := RunTape(t, "testdata/echo.tape.yaml")
+
+	require.NotNil(t, result)
+	assert.True(t, result.Success, result.ErrorMessage)
+
+	var shots, resizes int
+	for _, action := range result.Actions {
+		switch action.Type {
+		case "screenshot":
+			shots++
+			assert.Contains(t, action.Details.(TrackingShot).Filename, "after_resize")
+		case "resize":
+			resizes++
+		}
+	}
+	assert.Equal(t, 1, shots)
+	assert.Equal(t, 1, resizes)
+}
+
+// TestParseTape_Errors reports invalid tapes with their line numbers
+func TestParseTape_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		tape string
+		want string
+	}{
+		{"unknown step", "model: tape-echo\nsteps:\n  - type: a\n  - jump: high\n", "bad.yaml:4: unknown step \"jump\""},
+		{"bad key", "model: tape-echo\nsteps:\n  - press: ctrl+nope\n", "bad.yaml:3:"},
+		{"bad size", "model: tape-echo\nsteps:\n  - resize: wide\n", "bad.yaml:3: size must be COLSxROWS"},
+		{"missing argument", "model: tape-echo\nsteps:\n  - type:\n", "bad.yaml:3: type takes one argument"},
+		{"two actions", "model: tape-echo\nsteps:\n  - type: a\n    press: enter\n", "bad.yaml:3: a step is a single"},
+		{"unknown config", "model: tape-echo\nconfig:\n  colour: red\n", "field colour not found"},
+		{"no model", "steps:\n  - type: a\n", "does not name a model"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTape("bad.yaml", []byte(tt.tape))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// TestTapeRun_TripReportsLine stops at the failing step and names its line
+func TestTapeRun_TripReportsLine(t *testing.T) {
+	tape, err := ParseTape("failing.yaml", []byte(strings.Join([]string{
+		"model: tape-echo",
+		"config:",
+		"  timeout: 2s",
+		"  typing_speed: 0s",
+		"steps:",
+		"  - type: hi",
+		"  - assert_view: goodbye",
+		"  - resize: 30x5",
+	}, "\n")))
+	require.NoError(t, err)
+
+	recorder := &recordingTB{TB: t}
+	result := tape.Run(recorder)
+
+	assert.False(t, result.Success)
+	require.Len(t, recorder.errors, 1)
+	assert.True(t, strings.HasPrefix(recorder.errors[0], "failing.yaml:7: assert_view \"goodbye\""), recorder.errors[0])
+	assert.Contains(t, result.ErrorMessage, "failing.yaml:7: View does not contain expected text")
+	for _, action := range result.Actions {
+		assert.NotEqual(t, "resize", action.Type, "steps after the trip must not run")
+	}
+}
+
+// TestTapeRun_UnknownModel lists the registered models
+func TestTapeRun_UnknownModel(t *testing.T) {
+	tape := &Tape{Name: "missing.yaml", Model: "nope", Config: TapeConfig{}}
+	timeout := time.Second
+	tape.Config.Timeout = &timeout
+
+	recorder := &recordingTB{TB: t}
+	result := tape.Run(recorder)
+
+	assert.False(t, result.Success)
+	require.Len(t, recorder.errors, 1)
+	assert.Contains(t, recorder.errors[0], `unknown model "nope"`)
+	assert.Contains(t, recorder.errors[0], "tape-echo")
+}
@@ -0,0 +1,321 @@
+package steadicam
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// VHS defaults, used to turn a tape's pixel dimensions into terminal cells
+const (
+	vhsDefaultWidth      = 1200
+	vhsDefaultHeight     = 600
+	vhsDefaultFontSize   = 22
+	vhsDefaultPadding    = 60
+	vhsDefaultLineHeight = 1.0
+	vhsCellAspect        = 0.6 // Monospace cell width as a fraction of the font size
+)
+
+// vhsKeys are the VHS key commands, which take an optional repeat count
+var vhsKeys = map[string]string{
+	"Backspace": "backspace",
+	"Delete":    "delete",
+	"Insert":    "insert",
+	"Enter":     "enter",
+	"Escape":    "esc",
+	"Space":     "space",
+	"Tab":       "tab",
+	"Up":        "up",
+	"Down":      "down",
+	"Left":      "left",
+	"Right":     "right",
+	"PageUp":    "pgup",
+	"PageDown":  "pgdown",
+	"Home":      "home",
+	"End":       "end",
+}
+
+// vhsCosmetic lists commands and settings that only shape VHS's recording
+var vhsCosmetic = map[string]bool{
+	"Output": true, "Require": true, "Env": true, "Hide": true, "Show": true,
+	"Shell": true, "FontFamily": true, "LetterSpacing": true, "TypingSpeed": true,
+	"Theme": true, "Framerate": true, "PlaybackSpeed": true, "LoopOffset": true,
+	"WindowBar": true, "WindowBarSize": true, "BorderRadius": true, "MarginFill": true,
+	"CursorBlink": true, "WaitTimeout": true, "WaitPattern": true,
+}
+
+// vhsCommand splits "Wait+Screen@10s" into its name, scope and speed suffixes
+var vhsCommand = regexp.MustCompile(`^([A-Za-z]+(?:\+[A-Za-z0-9]+)*?)(\+Screen|\+Line)?(@\S+)?$`)
+
+// LoadVHSTape reads a charmbracelet VHS tape file. See ParseVHSTape.
+func LoadVHSTape(path string) (*Tape, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseVHSTape(path, data)
+}
+
+// RunVHSTape runs a VHS tape headlessly against model, so the tape that
+// records a demo also tests it and regenerates its screenshots without a
+// real terminal. Failures are reported at the tape's line like RunTape.
+//
+// Example:
+//
+//	func TestDemoTape(t *testing.T) {
+//		steadicam.RunVHSTape(t, "demo.tape", NewSearchREPL())
+//	}
+func RunVHSTape(t testing.TB, path string, model REPLModel) *StageResult {
+	t.Helper()
+
+	tape, err := LoadVHSTape(path)
+	if err != nil {
+		t.Errorf("%v", err)
+		return &StageResult{ErrorMessage: err.Error(), Error: err}
+	}
+	return tape.RunModel(t, model)
+}
+
+// ParseVHSTape parses the common commands of a charmbracelet VHS tape into
+// tape steps:
+//
+//	Type "text"            type
+//	Enter, Tab, Up, ... N  press, repeated N times
+//	Ctrl+X, Alt+Enter      press
+//	Sleep 500ms            wait (a bare number is seconds)
+//	Wait /regexp/          wait_for_match; a bare Wait waits for idle
+//	Copy "text", Paste     paste the copied text
+//	Screenshot out.png     screenshot
+//	Set Width/Height       terminal size in pixels, converted to cells
+//	                       using FontSize, Padding, Margin and LineHeight
+//
+// Speed suffixes such as Type@500ms and Wait timeouts are ignored because a
+// headless stage has no audience; waits use the stage timeout. Wait+Line
+// matches at any line end rather than only the last line. Commands that only
+// shape the recording (Output, Hide, Show, Require, Env and cosmetic
+// settings) are accepted and skipped. Anything else is an error naming its line.
+func ParseVHSTape(name string, data []byte) (*Tape, error) {
+	parser := &vhsParser{
+		tape:       &Tape{Name: name},
+		width:      vhsDefaultWidth,
+		height:     vhsDefaultHeight,
+		fontSize:   vhsDefaultFontSize,
+		padding:    vhsDefaultPadding,
+		lineHeight: vhsDefaultLineHeight,
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if err := parser.parseLine(line, text); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	parser.tape.Config.Width, parser.tape.Config.Height = parser.cells()
+	if parser.initialSize != nil {
+		parser.tape.Config.Width, parser.tape.Config.Height = parser.initialSize[0], parser.initialSize[1]
+	}
+	return parser.tape, nil
+}
+
+// vhsParser tracks the VHS settings that shape the terminal while parsing
+type vhsParser struct {
+	tape        *Tape
+	width       float64
+	height      float64
+	fontSize    float64
+	padding     float64
+	margin      float64
+	lineHeight  float64
+	clipboard   *string
+	initialSize *[2]int // Size before the first step, once steps have started
+}
+
+// parseLine turns one VHS command into zero or more steps
+func (p *vhsParser) parseLine(line int, text string) error {
+	word, rest, _ := strings.Cut(text, " ")
+	rest = strings.TrimSpace(rest)
+
+	match := vhsCommand.FindStringSubmatch(word)
+	if match == nil {
+		return fmt.Errorf("invalid command %q", word)
+	}
+	command, scope := match[1], match[2]
+
+	if vhsCosmetic[command] {
+		return nil
+	}
+	if key, ok := vhsKeys[command]; ok {
+		return p.addKey(line, key, rest)
+	}
+	if strings.Contains(command, "+") {
+		if _, err := ParseKey(command); err != nil {
+			return err
+		}
+		return p.addKey(line, command, rest)
+	}
+
+	switch command {
+	case "Type":
+		text, err := vhsString(rest)
+		if err != nil {
+			return err
+		}
+		p.add(line, "type", text)
+	case "Sleep":
+		duration, err := vhsDuration(rest)
+		if err != nil {
+			return err
+		}
+		p.add(line, "wait", duration.String())
+	case "Wait":
+		if rest == "" {
+			p.add(line, "wait_for_idle")
+			return nil
+		}
+		if len(rest) < 2 || !strings.HasPrefix(rest, "/") || !strings.HasSuffix(rest, "/") {
+			return fmt.Errorf("Wait takes a /regexp/, got %q", rest)
+		}
+		pattern := rest[1 : len(rest)-1]
+		if scope != "+Screen" {
+			pattern = "(?m)" + pattern
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return err
+		}
+		p.add(line, "wait_for_match", pattern)
+	case "Copy":
+		text, err := vhsString(rest)
+		if err != nil {
+			return err
+		}
+		p.clipboard = &text
+	case "Paste":
+		if p.clipboard == nil {
+			return fmt.Errorf("Paste before any Copy")
+		}
+		p.add(line, "paste", *p.clipboard)
+	case "Screenshot":
+		if rest == "" {
+			return fmt.Errorf("Screenshot takes a file name")
+		}
+		p.add(line, "screenshot", rest)
+	case "Set":
+		return p.set(line, rest)
+	case "Source":
+		return fmt.Errorf("Source is not supported; inline the sourced tape")
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
+
+// addKey adds a keypress step, repeated by an optional count
+func (p *vhsParser) addKey(line int, key, rest string) error {
+	count := 1
+	if rest != "" {
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid repeat count %q", rest)
+		}
+		count = n
+	}
+
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = key
+	}
+	p.add(line, "press", keys...)
+	return nil
+}
+
+// add appends a step, remembering the size the terminal started with
+func (p *vhsParser) add(line int, action string, args ...string) {
+	if p.initialSize == nil {
+		cols, rows := p.cells()
+		p.initialSize = &[2]int{cols, rows}
+	}
+	p.tape.Steps = append(p.tape.Steps, TapeStep{Line: line, Action: action, Args: args})
+}
+
+// set applies a Set command; size settings after the first step resize the terminal
+func (p *vhsParser) set(line int, rest string) error {
+	setting, value, _ := strings.Cut(rest, " ")
+	value = strings.TrimSpace(value)
+	if vhsCosmetic[setting] {
+		return nil
+	}
+
+	var target *float64
+	switch setting {
+	case "Width":
+		target = &p.width
+	case "Height":
+		target = &p.height
+	case "FontSize":
+		target = &p.fontSize
+	case "Padding":
+		target = &p.padding
+	case "Margin":
+		target = &p.margin
+	case "LineHeight":
+		target = &p.lineHeight
+	default:
+		return fmt.Errorf("unknown setting %q", setting)
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid %s %q", setting, value)
+	}
+	*target = n
+
+	cols, rows := p.cells()
+	if cols < 1 || rows < 1 {
+		return fmt.Errorf("Set %s %s leaves no room for the terminal", setting, value)
+	}
+	if p.initialSize != nil {
+		p.tape.Steps = append(p.tape.Steps, TapeStep{Line: line, Action: "resize", Args: []string{fmt.Sprintf("%dx%d", cols, rows)}})
+	}
+	return nil
+}
+
+// cells converts the pixel dimensions into terminal columns and rows
+func (p *vhsParser) cells() (int, int) {
+	inset := 2 * (p.padding + p.margin)
+	cols := (p.width - inset) / (p.fontSize * vhsCellAspect)
+	rows := (p.height - inset) / (p.fontSize * p.lineHeight)
+	return int(cols), int(rows)
+}
+
+// vhsString unquotes a VHS string, which may use double, single or back quotes
+func vhsString(s string) (string, error) {
+	if len(s) >= 2 {
+		quote := s[0]
+		if (quote == '"' || quote == '\'' || quote == '`') && s[len(s)-1] == quote {
+			return s[1 : len(s)-1], nil
+		}
+	}
+	return "", fmt.Errorf("expected a quoted string, got %q", s)
+}
+
+// vhsDuration parses a VHS duration, where a bare number means seconds
+func vhsDuration(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
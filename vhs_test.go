@@ -0,0 +1,101 @@
+package steadicam
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunVHSTape_File runs a VHS demo tape against a model
+func TestRunVHSTape_File(t *testing.T) {
+	model := &mockREPLForInteractions{mode: "ready"}
+	result := RunVHSTape(t, "testdata/echo.tape", model)
+
+	require.NotNil(t, result)
+	assert.True(t, result.Success, result.ErrorMessage)
+	assert.Equal(t, "hel", model.CurrentInput())
+	assert.Equal(t, "executed", model.CurrentMode())
+}
+
+// TestParseVHSTape_Steps maps VHS commands onto tape steps
+func TestParseVHSTape_Steps(t *testing.T) {
+	tape, err := ParseVHSTape("demo.tape", []byte(`# comment
+Output demo.gif
+Set Width 1200
+Set Height 600
+Type@200ms "ls -la"
+Enter
+Tab@100ms 2
+Ctrl+C
+Sleep 0.5
+Sleep 250ms
+Wait
+Wait+Screen /ready/
+Copy 'pasted'
+Paste
+Screenshot shots/ls.png
+Set Height 380
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 81, tape.Config.Width)
+	assert.Equal(t, 21, tape.Config.Height)
+	assert.Equal(t, []TapeStep{
+		{Line: 5, Action: "type", Args: []string{"ls -la"}},
+		{Line: 6, Action: "press", Args: []string{"enter"}},
+		{Line: 7, Action: "press", Args: []string{"tab", "tab"}},
+		{Line: 8, Action: "press", Args: []string{"Ctrl+C"}},
+		{Line: 9, Action: "wait", Args: []string{"500ms"}},
+		{Line: 10, Action: "wait", Args: []string{"250ms"}},
+		{Line: 11, Action: "wait_for_idle"},
+		{Line: 12, Action: "wait_for_match", Args: []string{"ready"}},
+		{Line: 14, Action: "paste", Args: []string{"pasted"}},
+		{Line: 15, Action: "screenshot", Args: []string{"shots/ls.png"}},
+		{Line: 16, Action: "resize", Args: []string{"81x11"}},
+	}, tape.Steps)
+}
+
+// TestParseVHSTape_Errors reports unsupported commands with their line numbers
+func TestParseVHSTape_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		tape string
+		want string
+	}{
+		{"unknown command", "Type \"a\"\nJump\n", "bad.tape:2: unknown command \"Jump\""},
+		{"unquoted type", "Type hello\n", "bad.tape:1: expected a quoted string"},
+		{"bad repeat", "Enter twice\n", "bad.tape:1: invalid repeat count"},
+		{"bad key", "Ctrl+Nope\n", "bad.tape:1: unknown key"},
+		{"bad regexp", "Wait /(/\n", "bad.tape:1: error parsing regexp"},
+		{"paste without copy", "Paste\n", "bad.tape:1: Paste before any Copy"},
+		{"unknown setting", "Set Colour red\n", "bad.tape:1: unknown setting \"Colour\""},
+		{"tiny terminal", "Set Width 100\n", "leaves no room"},
+		{"source", "Source other.tape\n", "Source is not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseVHSTape("bad.tape", []byte(tt.tape))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// TestRunVHSTape_Screenshot writes screenshots to the path the tape names
+func TestRunVHSTape_Screenshot(t *testing.T) {
+	shot := filepath.Join(t.TempDir(), "docs", "hello.png")
+	tape, err := ParseVHSTape("shots.tape", []byte("Type \"hi\"\nScreenshot "+shot+"\n"))
+	require.NoError(t, err)
+
+	result := tape.RunModel(t, &mockREPLForInteractions{mode: "ready"})
+	assert.True(t, result.Success, result.ErrorMessage)
+
+	_, err = os.Stat(shot)
+	assert.NoError(t, err, "screenshot should be written where the tape says")
+	require.Len(t, result.TrackingShots(), 1)
+	assert.Equal(t, "hello", result.TrackingShots()[0].Label)
+}